@@ -0,0 +1,125 @@
+// Copyright (c) 2013 CloudFlare, Inc.
+
+package lrucache
+
+// ARCCache is a cache using the Adaptive Replacement Cache policy. It
+// splits entries between recently (T1) and frequently (T2) used ones
+// and remembers keys recently evicted from each in ghost lists (B1
+// and B2). A miss on a ghost key shifts the target size of T1 towards
+// the list that would have kept it, so the cache tunes itself to the
+// workload. Otherwise it's an LRUCache and supports the same API.
+type ARCCache[K comparable, T any] struct {
+	LRUCache[K, T]
+}
+
+// Create new ARC cache instance. Allocate all the needed memory,
+// ghost entries included. O(capacity)
+func NewARCCache[K comparable, T any](capacity uint, opts ...Option) *ARCCache[K, T] {
+	c := &ARCCache[K, T]{}
+	c.init(capacity, append(opts[:len(opts):len(opts)], WithPolicy(PolicyARC))...)
+	return c
+}
+
+var _ Cache[string, any] = (*ARCCache[string, any])(nil)
+
+// Resident entries are linked in t1 or t2, ghosts in b1 or b2.
+type arcPolicy[K comparable, T any] struct {
+	t1, t2   list[K, T]
+	b1, b2   list[K, T]
+	ghosts   ghostTable[K, T]
+	capacity int
+	p        int // target size of t1
+}
+
+func newARCPolicy[K comparable, T any](capacity int) *arcPolicy[K, T] {
+	p := &arcPolicy[K, T]{capacity: capacity}
+	p.t1.Init()
+	p.t2.Init()
+	p.b1.Init()
+	p.b2.Init()
+	p.ghosts.init(capacity)
+	return p
+}
+
+func (p *arcPolicy[K, T]) add(e *entry[K, T]) {
+	g := p.ghosts.find(e.key)
+	if g == nil {
+		p.t1.PushElementFront(&e.element)
+		return
+	}
+
+	// Seen recently, adapt the target size of t1 towards the list
+	// which would have kept the key.
+	if g.element.list == &p.b1 {
+		p.p = min(p.capacity, p.p+max(p.b2.Len()/p.b1.Len(), 1))
+	} else {
+		p.p = max(0, p.p-max(p.b1.Len()/p.b2.Len(), 1))
+	}
+	p.ghosts.forget(g)
+	p.t2.PushElementFront(&e.element)
+}
+
+func (p *arcPolicy[K, T]) touch(e *entry[K, T]) {
+	if e.element.list == &p.t2 {
+		p.t2.MoveToFront(&e.element)
+		return
+	}
+	p.t1.Remove(&e.element)
+	p.t2.PushElementFront(&e.element)
+}
+
+func (p *arcPolicy[K, T]) remove(e *entry[K, T], reason EvictReason) {
+	from := e.element.list
+	from.Remove(&e.element)
+
+	// Only keys pushed out for capacity are worth remembering.
+	if reason != EvictCapacity {
+		return
+	}
+	if from == &p.t1 {
+		p.remember(e.key, &p.b1)
+	} else {
+		p.remember(e.key, &p.b2)
+	}
+}
+
+func (p *arcPolicy[K, T]) victim() *entry[K, T] {
+	if el := p.t1.Back(); el != nil && (p.t1.Len() > p.p || p.t2.Len() == 0) {
+		return el.Value
+	}
+	if el := p.t2.Back(); el != nil {
+		return el.Value
+	}
+	if el := p.t1.Back(); el != nil {
+		return el.Value
+	}
+	return nil
+}
+
+func (p *arcPolicy[K, T]) len() int {
+	return p.t1.Len() + p.t2.Len()
+}
+
+// Add a ghost of key to list b1 or b2, recycling the oldest ghost if
+// all are in use. |t1|+|b1| and |b1|+|b2| stay within capacity.
+func (p *arcPolicy[K, T]) remember(key K, to *list[K, T]) {
+	for !p.ghosts.remember(key, to) {
+		if p.b1.Len() > 0 && (p.t1.Len()+p.b1.Len() >= p.capacity || p.b2.Len() == 0) {
+			p.ghosts.forget(p.b1.Back().Value)
+		} else if p.b2.Len() > 0 {
+			p.ghosts.forget(p.b2.Back().Value)
+		} else {
+			return
+		}
+	}
+}
+
+func (p *arcPolicy[K, T]) resize(capacity int) {
+	p.capacity = capacity
+	p.p = min(p.p, capacity)
+	p.ghosts.resize(capacity, &p.b1, &p.b2)
+}
+
+func (p *arcPolicy[K, T]) walk(fn func(e *entry[K, T]) bool) {
+	_ = p.t1.walkBackward(fn) && p.t2.walkBackward(fn)
+}
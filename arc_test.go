@@ -0,0 +1,81 @@
+package lrucache
+
+import (
+	"testing"
+	"time"
+)
+
+func TestARCGhosts(t *testing.T) {
+	t.Parallel()
+	c := NewARCCache[string, string](4)
+	p := c.policy.(*arcPolicy[string, string])
+
+	for _, k := range []string{"a", "b", "c", "d"} {
+		c.Set(k, "v"+k, time.Time{})
+	}
+	c.Get("a")
+	c.Get("b")
+	if p.t1.Len() != 2 || p.t2.Len() != 2 {
+		t.Error("expecting entries read twice in t2")
+	}
+
+	c.Set("e", "ve", time.Time{})
+	if _, ok := c.GetQuiet("c"); ok {
+		t.Error("expecting least recent entry of t1 to be evicted")
+	}
+	if p.b1.Len() != 1 {
+		t.Error("expecting a ghost in b1")
+	}
+
+	// A ghost hit grows the target size of t1 and goes to t2.
+	c.Set("c", "vc", time.Time{})
+	if p.p != 1 || p.b1.Len() != 1 || p.t2.Len() != 3 {
+		t.Errorf("unexpected state p=%d b1=%d t2=%d", p.p, p.b1.Len(), p.t2.Len())
+	}
+
+	// Deleted keys don't leave ghosts.
+	c.Del("a")
+	if p.b1.Len()+p.b2.Len() != 1 || c.Len() != 3 {
+		t.Error("expecting no new ghost")
+	}
+}
+
+func TestARCScan(t *testing.T) {
+	t.Parallel()
+	var c Cache[int, int] = NewARCCache[int, int](100)
+
+	for round := 0; round < 2; round++ {
+		for i := 0; i < 50; i++ {
+			if _, ok := c.Get(i); !ok {
+				c.Set(i, i, time.Time{})
+			}
+		}
+	}
+	for i := 1000; i < 2000; i++ {
+		c.Set(i, i, time.Time{})
+	}
+	for i := 0; i < 50; i++ {
+		if _, ok := c.GetQuiet(i); !ok {
+			t.Errorf("expecting %d to survive a scan", i)
+		}
+	}
+	if c.Len() != 100 {
+		t.Error("expecting different length")
+	}
+}
+
+func TestARCExpiry(t *testing.T) {
+	t.Parallel()
+	c := NewARCCache[string, string](2)
+
+	now := time.Now()
+	c.SetNow("a", "va", now.Add(time.Second), now)
+	c.SetNow("b", "vb", time.Time{}, now)
+	if _, ok := c.GetNotStaleNow("a", now.Add(2*time.Second)); ok {
+		t.Error("expecting miss")
+	}
+	c.SetNow("c", "vc", now.Add(time.Second), now)
+	if c.ExpireNow(now.Add(2*time.Second)) != 1 || c.Len() != 1 {
+		t.Error("expecting expiry")
+	}
+}
@@ -0,0 +1,127 @@
+// Copyright (c) 2013 CloudFlare, Inc.
+
+package lrucache
+
+import (
+	"time"
+)
+
+// Item is a cache entry passed to SetMulti.
+type Item[K comparable, T any] struct {
+	Key    K
+	Value  T
+	Expire time.Time // zero means the item never expires
+}
+
+// GetMulti gets keys from the cache, making sure they're not stale,
+// and updates their LRU score, taking the lock once. values and found
+// are in the order of keys, values of missing keys are zero.
+func (b *LRUCache[K, T]) GetMulti(keys []K) (values []T, found []bool) {
+	values, found = make([]T, len(keys)), make([]bool, len(keys))
+
+	b.lock.Lock()
+	defer b.unlock()
+
+	now := b.clock.Now()
+	for i, key := range keys {
+		values[i], found[i] = b.getNotStale(key, now)
+	}
+	return values, found
+}
+
+// SetMulti adds items to the cache overwriting existing ones, taking
+// the lock once. Later items win over earlier ones with the same key.
+func (b *LRUCache[K, T]) SetMulti(items []Item[K, T]) {
+	b.lock.Lock()
+	defer b.unlock()
+
+	now := b.clock.Now()
+	for _, it := range items {
+		b.set(it.Key, it.Value, b.weigh(it.Key, it.Value), b.jitter(it.Expire, now), now)
+	}
+}
+
+// DelMulti removes keys from the cache, taking the lock once. Returns
+// the number of entries removed.
+func (b *LRUCache[K, T]) DelMulti(keys []K) int {
+	b.lock.Lock()
+	defer b.unlock()
+
+	n := 0
+	for _, key := range keys {
+		if e := b.lookup(key); e != nil {
+			b.removeEntry(e, EvictDeleted)
+			n++
+		}
+	}
+	return n
+}
+
+// Indexes of n keys grouped by bucket.
+func (m *MultiLRUCache[K, T]) byBucket(n int, key func(i int) K) [][]int {
+	buckets := make([][]int, len(m.cache))
+	for i := 0; i < n; i++ {
+		bucket := m.bucketNo(key(i))
+		buckets[bucket] = append(buckets[bucket], i)
+	}
+	return buckets
+}
+
+// GetMulti gets keys from the cache, making sure they're not stale,
+// taking the lock of every bucket once. See LRUCache.GetMulti.
+func (m *MultiLRUCache[K, T]) GetMulti(keys []K) (values []T, found []bool) {
+	values, found = make([]T, len(keys)), make([]bool, len(keys))
+
+	now := m.clock.Now()
+	for bucket, idx := range m.byBucket(len(keys), func(i int) K { return keys[i] }) {
+		if len(idx) == 0 {
+			continue
+		}
+		c := m.cache[bucket]
+		c.lock.Lock()
+		for _, i := range idx {
+			values[i], found[i] = c.getNotStale(keys[i], now)
+		}
+		c.unlock()
+	}
+	return values, found
+}
+
+// SetMulti adds items to the cache taking the lock of every bucket
+// once. See LRUCache.SetMulti.
+func (m *MultiLRUCache[K, T]) SetMulti(items []Item[K, T]) {
+	now := m.clock.Now()
+	for bucket, idx := range m.byBucket(len(items), func(i int) K { return items[i].Key }) {
+		if len(idx) == 0 {
+			continue
+		}
+		c := m.cache[bucket]
+		c.lock.Lock()
+		for _, i := range idx {
+			it := items[i]
+			c.set(it.Key, it.Value, c.weigh(it.Key, it.Value), c.jitter(it.Expire, now), now)
+		}
+		c.unlock()
+	}
+}
+
+// DelMulti removes keys from the cache taking the lock of every
+// bucket once. See LRUCache.DelMulti.
+func (m *MultiLRUCache[K, T]) DelMulti(keys []K) int {
+	n := 0
+	for bucket, idx := range m.byBucket(len(keys), func(i int) K { return keys[i] }) {
+		if len(idx) == 0 {
+			continue
+		}
+		c := m.cache[bucket]
+		c.lock.Lock()
+		for _, i := range idx {
+			if e := c.lookup(keys[i]); e != nil {
+				c.removeEntry(e, EvictDeleted)
+				n++
+			}
+		}
+		c.unlock()
+	}
+	return n
+}
@@ -0,0 +1,82 @@
+package lrucache
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestBatch(t *testing.T) {
+	t.Parallel()
+	b := NewLRUCache[string, int](10)
+
+	past := time.Now().Add(-time.Second)
+	b.SetMulti([]Item[string, int]{{"a", 1, time.Time{}}, {"b", 2, past}, {"c", 3, time.Time{}}, {"a", 4, time.Time{}}})
+	if b.Len() != 3 {
+		t.Error("expecting 3 entries")
+	}
+
+	values, found := b.GetMulti([]string{"c", "x", "b", "a"})
+	want := []int{3, 0, 0, 4}
+	for i := range want {
+		if values[i] != want[i] || found[i] != (want[i] != 0) {
+			t.Errorf("%d: got %d %v", i, values[i], found[i])
+		}
+	}
+
+	if n := b.DelMulti([]string{"a", "x", "c"}); n != 2 {
+		t.Errorf("expecting 2 deletions, got %d", n)
+	}
+	if b.Len() != 0 {
+		t.Error("expecting empty cache")
+	}
+}
+
+func TestMultiLRUBatch(t *testing.T) {
+	t.Parallel()
+	m := NewMultiLRUCache[string, int](4, 100)
+
+	var items []Item[string, int]
+	var keys []string
+	for i := 0; i < 100; i++ {
+		key := fmt.Sprint(i)
+		items = append(items, Item[string, int]{key, i, time.Time{}})
+		keys = append(keys, key)
+	}
+	m.SetMulti(items)
+	if m.Len() != 100 {
+		t.Errorf("expecting 100 entries, got %d", m.Len())
+	}
+
+	keys = append(keys, "missing")
+	values, found := m.GetMulti(keys)
+	for i := 0; i < 100; i++ {
+		if !found[i] || values[i] != i {
+			t.Errorf("%d: got %d %v", i, values[i], found[i])
+		}
+	}
+	if found[100] {
+		t.Error("expecting miss")
+	}
+
+	if n := m.DelMulti(keys); n != 100 {
+		t.Errorf("expecting 100 deletions, got %d", n)
+	}
+	if m.Len() != 0 {
+		t.Error("expecting empty cache")
+	}
+}
+
+func BenchmarkGetMultiMultiLRU(bb *testing.B) {
+	bb.ReportAllocs()
+	b := filledMultiLRU(time.Time{})
+
+	keys := make([]string, 100)
+	for i := range keys {
+		keys[i] = randomString(2)
+	}
+	bb.ResetTimer()
+	for i := 0; i < bb.N; i++ {
+		b.GetMulti(keys)
+	}
+}
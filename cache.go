@@ -7,7 +7,7 @@
 // O(1). Modification O(log(n)) if expiry is used, O(1)
 // otherwise.
 //
-// This package exports three things:
+// This package exports these things:
 //
 //	LRUCache: is the main implementation. It supports multithreading by
 //	    using guarding mutex lock.
@@ -18,25 +18,39 @@
 //	    data structure instead of LRUCache if you have lock
 //	    contention issues.
 //
-//	Cache interface: Both implementations fulfill it.
+//	ARCCache: is an LRUCache using the self tuning Adaptive
+//	    Replacement Cache eviction policy.
+//
+//	SieveCache, S3FIFOCache: are LRUCaches using the SIEVE and S3-FIFO
+//	    eviction policies, which don't reorder entries on reads.
+//
+//	Cache interface: All the implementations fulfill it.
+//
+// All of them are generic over a comparable key type K and a value
+// type T. StringLRUCache, StringMultiLRUCache and StringCache are
+// shorthands for the common string keyed case.
+//
+// Entries are evicted in least recently used order unless another
+// policy is selected with WithPolicy.
 package lrucache
 
 import (
 	"time"
 )
 
-// Cache interface is fulfilled by the LRUCache and MultiLRUCache
-// implementations.
-type Cache[T any] interface {
+// Cache interface is fulfilled by the LRUCache, MultiLRUCache, ARCCache,
+// SieveCache and S3FIFOCache implementations. Keys may be of any
+// comparable type.
+type Cache[K comparable, T any] interface {
 	// Get Methods not needing to know current time.
 	//
 	// Get a key from the cache, possibly stale. Update its LRU
 	// score.
-	Get(key string) (value T, ok bool)
+	Get(key K) (value T, ok bool)
 	// GetQuiet Get a key from the cache, possibly stale. Don't modify its LRU score. O(1)
-	GetQuiet(key string) (value T, ok bool)
+	GetQuiet(key K) (value T, ok bool)
 	// Del Get and remove a key from the cache.
-	Del(key string) (value T, ok bool)
+	Del(key K) (value T, ok bool)
 	// Clear Evict all items from the cache.
 	Clear() int
 	// Len Number of entries used in the LRU
@@ -44,27 +58,35 @@ type Cache[T any] interface {
 	// Capacity Get the total capacity of the LRU
 	Capacity() int
 
-	// Set Methods use time.Now() when necessary to determine expiry.
+	// Set Methods use the Clock when necessary to determine expiry.
 	//
 	// Add an item to the cache overwriting existing one if it
 	// exists.
-	Set(key string, T, expire time.Time)
+	Set(key K, value T, expire time.Time)
 	// GetNotStale get a key from the cache, make sure it's not stale. Update
 	// its LRU score.
-	GetNotStale(key string) (value T, ok bool)
+	GetNotStale(key K) (value T, ok bool)
 	// Expire Evict all the expired items.
 	Expire() int
 
 	// SetNow Methods allowing to explicitly specify time used to
-	// determine if items are expired.
+	// determine if items are expired. Prefer WithClock.
 	//
 	// Add an item to the cache overwriting existing one if it
 	// exists. Allows specifying current time required to expire an
 	// item when no more slots are used.
-	SetNow(key string, T, expire time.Time, now time.Time)
+	SetNow(key K, value T, expire time.Time, now time.Time)
 	// GetNotStaleNow Get a key from the cache, make sure it's not stale. Update
 	// its LRU score.
-	GetNotStaleNow(key string, now time.Time) (T, ok bool)
+	GetNotStaleNow(key K, now time.Time) (value T, ok bool)
 	// ExpireNow Evict items that expire before Now.
 	ExpireNow(now time.Time) int
 }
+
+// StringCache is the Cache interface keyed by strings.
+type StringCache[T any] = Cache[string, T]
+
+var (
+	_ Cache[string, any] = (*LRUCache[string, any])(nil)
+	_ Cache[string, any] = (*MultiLRUCache[string, any])(nil)
+)
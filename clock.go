@@ -0,0 +1,27 @@
+// Copyright (c) 2013 CloudFlare, Inc.
+
+package lrucache
+
+import (
+	"time"
+)
+
+// Clock tells the current time. Every method of the cache not taking
+// an explicit `now` argument, and the janitor, ask the clock.
+type Clock interface {
+	Now() time.Time
+}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+// WithClock replaces time.Now as the source of the current time, for
+// example with lrucachetest.FakeClock in tests.
+func WithClock(c Clock) Option {
+	return func(o *options) {
+		o.clock = c
+	}
+}
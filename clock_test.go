@@ -0,0 +1,55 @@
+package lrucache
+
+import (
+	"testing"
+	"time"
+
+	"GolangLRU/lrucachetest"
+)
+
+func TestClock(t *testing.T) {
+	t.Parallel()
+	clock := lrucachetest.NewFakeClock(time.Date(2013, 1, 1, 0, 0, 0, 0, time.UTC))
+	b := NewLRUCache[string, string](2, WithClock(clock))
+
+	b.Set("a", "va", clock.Now().Add(time.Minute))
+	b.Set("b", "vb", clock.Now().Add(time.Hour))
+
+	clock.Advance(30 * time.Second)
+	if _, ok := b.GetNotStale("a"); !ok {
+		t.Error("expecting hit")
+	}
+
+	clock.Advance(time.Minute)
+	if _, _, expired := b.GetStale("a"); !expired {
+		t.Error("expecting stale hit")
+	}
+
+	// The expired entry goes first, even though it was used last.
+	b.Set("c", "vc", time.Time{})
+	if _, ok := b.GetQuiet("a"); ok {
+		t.Error("expecting expired entry to be evicted")
+	}
+
+	clock.Advance(time.Hour)
+	if b.Expire() != 1 || b.Len() != 1 {
+		t.Error("expecting expiry")
+	}
+}
+
+func TestMultiLRUClock(t *testing.T) {
+	t.Parallel()
+	clock := lrucachetest.NewFakeClock(time.Now())
+	m := NewMultiLRUCache[int, int](4, 10, WithClock(clock))
+
+	for i := 0; i < 10; i++ {
+		m.Set(i, i, clock.Now().Add(time.Duration(i)*time.Second))
+	}
+	clock.Advance(5500 * time.Millisecond)
+	if n := m.Expire(); n != 6 {
+		t.Errorf("expecting 6 expired entries, got %d", n)
+	}
+	if _, ok := m.GetNotStale(9); !ok {
+		t.Error("expecting hit")
+	}
+}
@@ -0,0 +1,81 @@
+// Copyright (c) 2013 CloudFlare, Inc.
+
+package lrucache
+
+import (
+	"math"
+	"math/rand/v2"
+	"time"
+)
+
+// WithEarlyExpiration makes GetNotStale and GetOrLoad report a miss
+// shortly before an entry expires, with a probability growing as the
+// expiry gets closer (XFetch). The first caller seeing the miss
+// recomputes the value while the others are still served from the
+// cache, instead of all of them missing at the same instant. How
+// early depends on the cost of computing the entry, measured by
+// GetOrLoad or given to SetWithCost, times beta. A beta of 1 is a
+// good default, larger values recompute earlier. Entries without a
+// cost are never expired early.
+func WithEarlyExpiration(beta float64) Option {
+	return func(o *options) {
+		o.earlyBeta = max(beta, 0)
+	}
+}
+
+// WithTTLJitter shortens the time to live of every entry stored by
+// Set by a random amount up to fraction of it, between 0 and 1. It
+// spreads the expiry of entries set at the same time with the same
+// TTL.
+func WithTTLJitter(fraction float64) Option {
+	return func(o *options) {
+		o.ttlJitter = min(max(fraction, 0), 1)
+	}
+}
+
+// SetWithCost adds an item to the cache overwriting existing one if
+// it exists, recording that it took cost to compute. See
+// WithEarlyExpiration.
+func (b *LRUCache[K, T]) SetWithCost(key K, value T, expire time.Time, cost time.Duration) {
+	weight := b.weigh(key, value)
+
+	b.lock.Lock()
+	defer b.unlock()
+
+	now := b.clock.Now()
+	if e := b.set(key, value, weight, b.jitter(expire, now), now); e != nil && cost > 0 {
+		e.extend().cost = cost
+	}
+}
+
+// Randomly shorten expire, see WithTTLJitter.
+func (b *LRUCache[K, T]) jitter(expire, now time.Time) time.Time {
+	if b.ttlJitter == 0 || expire.IsZero() {
+		return expire
+	}
+	if now.IsZero() {
+		now = b.clock.Now()
+	}
+	ttl := expire.Sub(now)
+	if ttl <= 0 {
+		return expire
+	}
+	return expire.Add(-time.Duration(rand.Float64() * b.ttlJitter * float64(ttl)))
+}
+
+// Should a fresh entry be reported as a miss, see
+// WithEarlyExpiration.
+func (b *LRUCache[K, T]) expiresEarly(e *entry[K, T], now time.Time) bool {
+	if b.earlyBeta == 0 || e.ext == nil || e.ext.cost <= 0 || e.expire.IsZero() {
+		return false
+	}
+	// -log of a uniform number in (0, 1] is exponentially distributed.
+	early := float64(e.ext.cost) * b.earlyBeta * -math.Log(1-rand.Float64())
+	return !now.Add(time.Duration(min(early, math.MaxInt64))).Before(e.expire)
+}
+
+// SetWithCost adds an item recording its cost, see
+// LRUCache.SetWithCost.
+func (m *MultiLRUCache[K, T]) SetWithCost(key K, value T, expire time.Time, cost time.Duration) {
+	m.cache[m.bucketNo(key)].SetWithCost(key, value, expire, cost)
+}
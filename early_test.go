@@ -0,0 +1,75 @@
+package lrucache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"GolangLRU/lrucachetest"
+)
+
+func TestEarlyExpiration(t *testing.T) {
+	t.Parallel()
+	clock := lrucachetest.NewFakeClock(time.Date(2013, 1, 1, 0, 0, 0, 0, time.UTC))
+	b := NewLRUCache[string, string](10, WithClock(clock), WithEarlyExpiration(1))
+
+	_, err := b.GetOrLoad(context.Background(), "a", func(context.Context) (string, time.Time, error) {
+		clock.Advance(10 * time.Second)
+		return "va", clock.Now().Add(time.Hour), nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	b.Set("b", "vb", clock.Now().Add(time.Hour))
+
+	misses := func(key string) (n int) {
+		for i := 0; i < 1000; i++ {
+			if _, ok := b.GetNotStale(key); !ok {
+				n++
+			}
+		}
+		return n
+	}
+
+	if n := misses("a"); n != 0 {
+		t.Errorf("expecting no early miss an hour before expiry, got %d", n)
+	}
+	clock.Advance(time.Hour - time.Second)
+	if n := misses("a"); n == 0 || n == 1000 {
+		t.Errorf("expecting some early misses a second before expiry, got %d", n)
+	}
+	if n := misses("b"); n != 0 {
+		t.Errorf("expecting no early miss without a cost, got %d", n)
+	}
+	if _, ok, expired := b.GetStale("a"); !ok || expired {
+		t.Error("expecting entry to be kept")
+	}
+}
+
+func TestTTLJitter(t *testing.T) {
+	t.Parallel()
+	clock := lrucachetest.NewFakeClock(time.Date(2013, 1, 1, 0, 0, 0, 0, time.UTC))
+	b := NewLRUCache[int, int](100, WithClock(clock), WithTTLJitter(0.5))
+
+	expire := clock.Now().Add(time.Hour)
+	for i := 0; i < 100; i++ {
+		b.Set(i, i, expire)
+	}
+	b.Set(100, 100, time.Time{})
+
+	clock.Advance(30 * time.Minute)
+	b.ExpireNow(clock.Now())
+	if b.Len() != 100 {
+		t.Error("expecting no entry expired before half of the TTL")
+	}
+	clock.Advance(15 * time.Minute)
+	b.ExpireNow(clock.Now())
+	if b.Len() == 100 || b.Len() == 1 {
+		t.Errorf("expecting expiry to be spread, %d left", b.Len())
+	}
+	clock.Advance(16 * time.Minute)
+	b.ExpireNow(clock.Now())
+	if b.Len() != 1 {
+		t.Error("expecting every entry expired after the TTL")
+	}
+}
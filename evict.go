@@ -0,0 +1,52 @@
+// Copyright (c) 2013 CloudFlare, Inc.
+
+package lrucache
+
+// EvictReason tells why an entry left the cache.
+type EvictReason int
+
+const (
+	EvictCapacity EvictReason = iota // pushed out to make room for another entry
+	EvictExpired                     // expired and purged
+	EvictReplaced                    // overwritten by a Set for the same key
+	EvictDeleted                     // removed by Del
+	EvictCleared                     // removed by Clear
+
+	evictReasons = iota // number of reasons, keep it last
+)
+
+func (r EvictReason) String() string {
+	switch r {
+	case EvictCapacity:
+		return "capacity"
+	case EvictExpired:
+		return "expired"
+	case EvictReplaced:
+		return "replaced"
+	case EvictDeleted:
+		return "deleted"
+	case EvictCleared:
+		return "cleared"
+	}
+	return "unknown"
+}
+
+// An entry removed while holding the lock, waiting to be reported to
+// the OnEvict callback once the lock is released.
+type eviction[K comparable, T any] struct {
+	key    K
+	value  T
+	reason EvictReason
+}
+
+// Release the lock and only then deliver evictions gathered while
+// holding it. This way callbacks are free to call back into the cache.
+func (b *LRUCache[K, T]) unlock() {
+	evicted := b.evicted
+	b.evicted = nil
+	b.lock.Unlock()
+
+	for i := range evicted {
+		b.OnEvict(evicted[i].key, evicted[i].value, evicted[i].reason)
+	}
+}
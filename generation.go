@@ -0,0 +1,97 @@
+// Copyright (c) 2013 CloudFlare, Inc.
+
+package lrucache
+
+import (
+	"sync/atomic"
+)
+
+// WithGenerations makes Clear O(1). Instead of removing the entries
+// it starts a new generation, entries of older generations are
+// treated as missing and reclaimed lazily, before any other entry when
+// room is needed, or by the janitor. They're reported to OnEvict with
+// EvictCleared as they're reclaimed, which takes O(capacity) per Clear
+// in total. Clear of a MultiLRUCache becomes a single atomic operation
+// across all the buckets.
+func WithGenerations() Option {
+	return func(o *options) {
+		o.generations = true
+	}
+}
+
+// Share the generation counter of a multicache with its buckets.
+func withGeneration(gen *atomic.Uint64) Option {
+	return func(o *options) {
+		o.gen = gen
+	}
+}
+
+// Catch up with the generation counter. Every entry is stale after a
+// Clear. Must be called with the lock held.
+func (b *LRUCache[K, T]) sync() {
+	if b.gen == nil {
+		return
+	}
+	if g := b.gen.Load(); g != b.seenGen {
+		b.seenGen = g
+		b.staleLen = b.used()
+		b.stalePins = b.pinned.Len()
+		b.pinnedWeight = 0
+		b.staleBlock, b.staleIdx = 0, 0
+	}
+}
+
+// Is the entry from a cleared generation. Must be called with the lock
+// held, after sync.
+func (b *LRUCache[K, T]) stale(e *entry[K, T]) bool {
+	return b.gen != nil && e.ext.gen != b.seenGen
+}
+
+// Entry for a key, nil if it's missing or cleared. Must be called
+// with the lock held.
+func (b *LRUCache[K, T]) lookup(key K) *entry[K, T] {
+	b.sync()
+	e := b.table[key]
+	if e == nil || b.stale(e) {
+		return nil
+	}
+	return e
+}
+
+// Number of entries not cleared. Must be called with the lock held.
+func (b *LRUCache[K, T]) len() int {
+	b.sync()
+	return b.used() - b.staleLen
+}
+
+// Remove at most limit cleared entries, whatever the policy thinks of
+// them. Must be called with the lock held.
+func (b *LRUCache[K, T]) reclaimStale(limit int) int {
+	b.sync()
+	n := 0
+	for ; n < limit && b.staleLen > 0; n++ {
+		e := b.nextStale()
+		if e == nil {
+			break
+		}
+		b.removeEntry(e, EvictCleared)
+	}
+	return n
+}
+
+// Continue the scan of all the entries for a stale one in use. Entries
+// behind the scan are never stale, they were stored since the last
+// generation started, so it takes O(capacity) per generation.
+func (b *LRUCache[K, T]) nextStale() *entry[K, T] {
+	for ; b.staleBlock < len(b.blocks); b.staleBlock, b.staleIdx = b.staleBlock+1, 0 {
+		block := b.blocks[b.staleBlock]
+		for b.staleIdx < len(block) {
+			e := &block[b.staleIdx]
+			b.staleIdx++
+			if b.stale(e) && b.table[e.key] == e {
+				return e
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,115 @@
+package lrucache
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestGenerations(t *testing.T) {
+	t.Parallel()
+	b := NewLRUCache[int, int](4, WithGenerations())
+	var cleared []int
+	b.OnEvict = func(key int, value int, reason EvictReason) {
+		if reason == EvictCleared {
+			cleared = append(cleared, key)
+		}
+	}
+
+	for i := 0; i < 4; i++ {
+		b.Set(i, i, time.Time{})
+	}
+	if n := b.Clear(); n != 4 {
+		t.Errorf("expecting 4 cleared, got %d", n)
+	}
+	if b.Len() != 0 {
+		t.Error("expecting empty cache")
+	}
+	if _, ok := b.Get(1); ok {
+		t.Error("expecting miss")
+	}
+	if len(cleared) != 0 {
+		t.Error("expecting entries to be reclaimed lazily")
+	}
+
+	b.Set(1, 10, time.Time{})
+	b.Set(4, 40, time.Time{})
+	if v, ok := b.Get(1); !ok || v != 10 {
+		t.Error("expecting hit")
+	}
+	if b.Len() != 2 {
+		t.Errorf("expecting 2 entries, got %d", b.Len())
+	}
+	if len(cleared) != 2 {
+		t.Errorf("expecting 2 entries reclaimed, got %v", cleared)
+	}
+	if n := len(slices.Collect(b.Keys())); n != 2 {
+		t.Errorf("expecting cleared entries to be skipped, got %d keys", n)
+	}
+
+	b.lock.Lock()
+	n := b.reclaimStale(janitorBatch)
+	b.unlock()
+	if n != 2 || len(cleared) != 4 {
+		t.Errorf("expecting remaining entries reclaimed, got %d", n)
+	}
+	if b.Stats().Evicted(EvictCleared) != 4 || b.Stats().Evicted(EvictReplaced) != 0 {
+		t.Error("expecting reclaimed entries counted as cleared")
+	}
+}
+
+func TestMultiLRUGenerations(t *testing.T) {
+	t.Parallel()
+	m := NewMultiLRUCache[int, int](4, 20, WithGenerations())
+
+	for i := 0; i < 20; i++ {
+		m.Set(i, i, time.Time{})
+	}
+	if n := m.Clear(); n != 20 {
+		t.Errorf("expecting 20 cleared, got %d", n)
+	}
+	if m.Len() != 0 {
+		t.Error("expecting empty cache")
+	}
+	for i := 0; i < 20; i++ {
+		if _, ok := m.Get(i); ok {
+			t.Error("expecting miss")
+		}
+	}
+
+	m.StartJanitor(time.Millisecond)
+	defer m.Stop()
+	deadline := time.Now().Add(time.Second)
+	for m.Stats().Evicted(EvictCleared) != 20 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if m.Stats().Evicted(EvictCleared) != 20 {
+		t.Error("expecting janitor to reclaim cleared entries")
+	}
+}
+
+func TestGenerationsFrequencyPolicy(t *testing.T) {
+	t.Parallel()
+	for _, p := range []Policy{PolicyLFU, PolicyTinyLFU, PolicyARC} {
+		b := NewLRUCache[int, int](4, WithPolicy(p), WithGenerations())
+		for i := 0; i < 4; i++ {
+			b.Set(i, i, time.Time{})
+		}
+		for j := 0; j < 5; j++ {
+			for i := 0; i < 4; i++ {
+				b.Get(i)
+			}
+		}
+		b.Clear()
+
+		for i := 10; i < 14; i++ {
+			b.Set(i, i, time.Time{})
+		}
+		if b.Len() != 4 {
+			t.Errorf("%v: expecting cleared entries to be evicted first, got %d entries", p, b.Len())
+		}
+		if b.Stats().Evicted(EvictCleared) != 4 || b.Stats().Evicted(EvictCapacity) != 0 {
+			t.Errorf("%v: expecting only cleared entries to be evicted", p)
+		}
+	}
+}
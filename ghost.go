@@ -0,0 +1,78 @@
+// Copyright (c) 2013 CloudFlare, Inc.
+
+package lrucache
+
+// Keys of recently evicted entries, used by policies that take past
+// evictions into account. Ghosts are entries holding only a key. They
+// are allocated on creation and linked either in a list owned by the
+// policy or in the free list.
+type ghostTable[K comparable, T any] struct {
+	table    map[K]*entry[K, T]
+	freeList list[K, T]
+	capacity int
+}
+
+func (g *ghostTable[K, T]) init(capacity int) {
+	g.table = make(map[K]*entry[K, T], capacity)
+	g.freeList.Init()
+	g.alloc(capacity)
+}
+
+func (g *ghostTable[K, T]) alloc(n int) {
+	arrayOfGhosts := make([]entry[K, T], n)
+	for i := range arrayOfGhosts {
+		e := &arrayOfGhosts[i]
+		e.element.Value = e
+		e.index = -1
+		g.freeList.PushElementBack(&e.element)
+	}
+	g.capacity += n
+}
+
+// Change the number of ghosts. When shrinking, free ghosts are
+// released first, then the oldest ones from lists in order.
+func (g *ghostTable[K, T]) resize(capacity int, lists ...*list[K, T]) {
+	if capacity > g.capacity {
+		g.alloc(capacity - g.capacity)
+	}
+	for ; g.capacity > capacity; g.capacity-- {
+		for _, l := range lists {
+			if g.freeList.Len() > 0 {
+				break
+			}
+			if l.Len() > 0 {
+				g.forget(l.Back().Value)
+			}
+		}
+		g.freeList.Remove(g.freeList.Front())
+	}
+}
+
+func (g *ghostTable[K, T]) find(key K) *entry[K, T] {
+	return g.table[key]
+}
+
+// Add a ghost of key in front of list l. Returns false if all the
+// ghosts are in use.
+func (g *ghostTable[K, T]) remember(key K, l *list[K, T]) bool {
+	if e := g.table[key]; e != nil {
+		g.forget(e)
+	}
+	if g.freeList.Len() == 0 {
+		return false
+	}
+
+	e := g.freeList.PopElementFront().Value
+	e.key = key
+	l.PushElementFront(&e.element)
+	g.table[key] = e
+	return true
+}
+
+func (g *ghostTable[K, T]) forget(e *entry[K, T]) {
+	e.element.list.Remove(&e.element)
+	delete(g.table, e.key)
+	var k K
+	e.key = k
+	g.freeList.PushElementFront(&e.element)
+}
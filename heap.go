@@ -13,17 +13,17 @@ import "sort"
 // Note that Push and Pop in this interface are for package heap's
 // implementation to call. To add and remove things from the heap,
 // use heap.Push and heap.Pop.
-type Interface[T any] interface {
+type Interface[K comparable, T any] interface {
 	sort.Interface
-	Push(x *entry[T]) // add x as element Len()
-	Pop() *entry[T]   // remove and return element Len() - 1.
+	Push(x *entry[K, T]) // add x as element Len()
+	Pop() *entry[K, T]   // remove and return element Len() - 1.
 }
 
 // HeapInit establishes the heap invariants required by the other routines in this package.
 // Init is idempotent with respect to the heap invariants
 // and may be called whenever the heap invariants may have been invalidated.
 // The complexity is O(n) where n = h.Len().
-func HeapInit[T any](h Interface[T]) {
+func HeapInit[K comparable, T any](h Interface[K, T]) {
 	// heapify
 	n := h.Len()
 	for i := n/2 - 1; i >= 0; i-- {
@@ -33,7 +33,7 @@ func HeapInit[T any](h Interface[T]) {
 
 // HeapPush pushes the element x onto the heap.
 // The complexity is O(log n) where n = h.Len().
-func HeapPush[T any](h Interface[T], x *entry[T]) {
+func HeapPush[K comparable, T any](h Interface[K, T], x *entry[K, T]) {
 	h.Push(x)
 	up(h, h.Len()-1)
 }
@@ -41,7 +41,7 @@ func HeapPush[T any](h Interface[T], x *entry[T]) {
 // HeapPop removes and returns the minimum element (according to Less) from the heap.
 // The complexity is O(log n) where n = h.Len().
 // Pop is equivalent to Remove(h, 0).
-func HeapPop[T any](h Interface[T]) *entry[T] {
+func HeapPop[K comparable, T any](h Interface[K, T]) *entry[K, T] {
 	n := h.Len() - 1
 	h.Swap(0, n)
 	down(h, 0, n)
@@ -50,7 +50,7 @@ func HeapPop[T any](h Interface[T]) *entry[T] {
 
 // HeapRemove removes and returns the element at index i from the heap.
 // The complexity is O(log n) where n = h.Len().
-func HeapRemove[T any](h Interface[T], i int) *entry[T] {
+func HeapRemove[K comparable, T any](h Interface[K, T], i int) *entry[K, T] {
 	n := h.Len() - 1
 	if n != i {
 		h.Swap(i, n)
@@ -65,13 +65,13 @@ func HeapRemove[T any](h Interface[T], i int) *entry[T] {
 // Changing the value of the element at index i and then calling Fix is equivalent to,
 // but less expensive than, calling Remove(h, i) followed by a Push of the new value.
 // The complexity is O(log n) where n = h.Len().
-func HeapFix[T any](h Interface[T], i int) {
+func HeapFix[K comparable, T any](h Interface[K, T], i int) {
 	if !down(h, i, h.Len()) {
 		up(h, i)
 	}
 }
 
-func up[T any](h Interface[T], j int) {
+func up[K comparable, T any](h Interface[K, T], j int) {
 	for {
 		i := (j - 1) / 2 // parent
 		if i == j || !h.Less(j, i) {
@@ -82,7 +82,7 @@ func up[T any](h Interface[T], j int) {
 	}
 }
 
-func down[T any](h Interface[T], i0, n int) bool {
+func down[K comparable, T any](h Interface[K, T], i0, n int) bool {
 	i := i0
 	for {
 		j1 := 2*i + 1
@@ -0,0 +1,79 @@
+// Copyright (c) 2013 CloudFlare, Inc.
+
+package lrucache
+
+import (
+	"time"
+)
+
+// WithEntryMetadata tracks when every entry was stored and last read
+// and how many times it was read, see Inspect. It costs a read of the
+// Clock on every access and 56 bytes per entry, allocated next to the
+// entries. Without it entries only carry a nil pointer.
+func WithEntryMetadata() Option {
+	return func(o *options) {
+		o.metadata = true
+	}
+}
+
+// Metadata of an entry, kept apart so that entries stay small without
+// WithEntryMetadata.
+type entryMeta struct {
+	inserted time.Time
+	accessed time.Time
+	accesses uint64
+}
+
+// Start the metadata of an entry stored at now, filled from the clock
+// only when needed.
+func (b *LRUCache[K, T]) resetMeta(e *entry[K, T], now time.Time) {
+	if !b.metadata {
+		return
+	}
+	if now.IsZero() {
+		now = b.clock.Now()
+	}
+	*e.meta = entryMeta{inserted: now}
+}
+
+// EntryInfo describes an entry of the cache, see Inspect. Inserted,
+// LastAccess and Accesses are only filled with WithEntryMetadata.
+type EntryInfo[K comparable, T any] struct {
+	Key    K
+	Value  T
+	Expire time.Time // zero if the entry never expires
+	Weight uint64    // see WithMaxWeight
+	Pinned bool      // see Pin
+
+	Inserted   time.Time // when the entry was stored by Set
+	LastAccess time.Time // zero if never read since
+	Accesses   uint64    // reads since stored
+}
+
+// Inspect describes an entry, possibly stale, without updating its
+// LRU score. O(1)
+func (b *LRUCache[K, T]) Inspect(key K) (info EntryInfo[K, T], ok bool) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	e := b.lookup(key)
+	if e == nil {
+		return info, false
+	}
+	info = EntryInfo[K, T]{
+		Key:    e.key,
+		Value:  e.value,
+		Expire: e.expire,
+		Weight: e.weight,
+		Pinned: e.pinned,
+	}
+	if m := e.meta; m != nil {
+		info.Inserted, info.LastAccess, info.Accesses = m.inserted, m.accessed, m.accesses
+	}
+	return info, true
+}
+
+// Inspect describes an entry, see LRUCache.Inspect.
+func (m *MultiLRUCache[K, T]) Inspect(key K) (info EntryInfo[K, T], ok bool) {
+	return m.cache[m.bucketNo(key)].Inspect(key)
+}
@@ -0,0 +1,53 @@
+package lrucache
+
+import (
+	"testing"
+	"time"
+
+	"GolangLRU/lrucachetest"
+)
+
+func TestInspect(t *testing.T) {
+	t.Parallel()
+	clock := lrucachetest.NewFakeClock(time.Date(2013, 1, 1, 0, 0, 0, 0, time.UTC))
+	b := NewLRUCache[string, string](2, WithClock(clock), WithEntryMetadata())
+
+	start := clock.Now()
+	b.Set("a", "va", start.Add(time.Hour))
+	clock.Advance(time.Second)
+	b.Set("b", "vb", time.Time{})
+	clock.Advance(time.Second)
+	b.Get("a")
+	b.GetNotStale("a")
+
+	info, ok := b.Inspect("a")
+	if !ok {
+		t.Fatal("expecting hit")
+	}
+	if info.Key != "a" || info.Value != "va" || !info.Expire.Equal(start.Add(time.Hour)) || info.Weight != 1 {
+		t.Errorf("unexpected info %+v", info)
+	}
+	if !info.Inserted.Equal(start) || !info.LastAccess.Equal(clock.Now()) || info.Accesses != 2 {
+		t.Errorf("unexpected metadata %+v", info)
+	}
+
+	// Inspecting doesn't make b more recently used than a.
+	b.Inspect("b")
+	b.Set("c", "vc", time.Time{})
+	if _, ok := b.Inspect("b"); ok {
+		t.Error("expecting b to be evicted")
+	}
+	if _, ok := b.Inspect("x"); ok {
+		t.Error("expecting miss")
+	}
+
+	c := NewLRUCache[string, string](2)
+	c.Set("a", "va", time.Time{})
+	c.Get("a")
+	if info, ok := c.Inspect("a"); !ok || info.Accesses != 0 || !info.Inserted.IsZero() {
+		t.Error("expecting no metadata")
+	}
+	if c.table["a"].meta != nil {
+		t.Error("expecting no metadata allocated")
+	}
+}
@@ -0,0 +1,66 @@
+// Copyright (c) 2013 CloudFlare, Inc.
+
+package lrucache
+
+import (
+	"reflect"
+	"strings"
+)
+
+// DelFunc removes every entry for which fn returns true. fn runs with
+// the lock held and must not use the cache. Returns the number of
+// entries removed. O(n)
+func (b *LRUCache[K, T]) DelFunc(fn func(key K, value T) bool) int {
+	b.lock.Lock()
+	defer b.unlock()
+
+	return b.delFunc(fn)
+}
+
+// See DelFunc. Must be called with the lock held.
+func (b *LRUCache[K, T]) delFunc(fn func(key K, value T) bool) int {
+	b.sync()
+	n := 0
+	for key, e := range b.table {
+		if !b.stale(e) && fn(key, e.value) {
+			b.removeEntry(e, EvictDeleted)
+			n++
+		}
+	}
+	return n
+}
+
+// DelPrefix removes every entry with a key starting with prefix. Only
+// string keys match, named string types included. It's a no-op for
+// caches with other key types. Returns the number of entries removed.
+// O(n)
+func (b *LRUCache[K, T]) DelPrefix(prefix string) int {
+	return b.DelFunc(hasPrefix[K, T](prefix))
+}
+
+func hasPrefix[K comparable, T any](prefix string) func(key K, value T) bool {
+	return func(key K, value T) bool {
+		if s, ok := any(key).(string); ok {
+			return strings.HasPrefix(s, prefix)
+		}
+		v := reflect.ValueOf(key)
+		return v.Kind() == reflect.String && strings.HasPrefix(v.String(), prefix)
+	}
+}
+
+// DelFunc removes every entry for which fn returns true, see
+// LRUCache.DelFunc. Buckets are processed one at a time, so it isn't
+// atomic with respect to concurrent writes to other buckets.
+func (m *MultiLRUCache[K, T]) DelFunc(fn func(key K, value T) bool) int {
+	n := 0
+	for _, c := range m.cache {
+		n += c.DelFunc(fn)
+	}
+	return n
+}
+
+// DelPrefix removes every entry with a key starting with prefix, see
+// LRUCache.DelPrefix.
+func (m *MultiLRUCache[K, T]) DelPrefix(prefix string) int {
+	return m.DelFunc(hasPrefix[K, T](prefix))
+}
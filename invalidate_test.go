@@ -0,0 +1,51 @@
+package lrucache
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestDelPrefix(t *testing.T) {
+	t.Parallel()
+	m := NewMultiLRUCache[string, int](4, 100)
+
+	for i := 0; i < 50; i++ {
+		m.Set(fmt.Sprintf("user:%d:name", i), i, time.Time{})
+		m.Set(fmt.Sprintf("user:%d:mail", i), i, time.Time{})
+	}
+
+	if n := m.DelPrefix("user:1:"); n != 2 {
+		t.Errorf("expecting 2 deletions, got %d", n)
+	}
+	if n := m.DelPrefix("user:1"); n != 20 {
+		t.Errorf("expecting 20 deletions, got %d", n)
+	}
+	if _, ok := m.Get("user:2:name"); !ok {
+		t.Error("expecting hit")
+	}
+	if m.Stats().Evicted(EvictDeleted) != 22 {
+		t.Error("expecting deletions to be counted")
+	}
+
+	if n := m.DelFunc(func(key string, value int) bool { return value%2 == 0 }); n != 40 {
+		t.Errorf("expecting 40 deletions, got %d", n)
+	}
+	if m.Len() != 38 {
+		t.Errorf("expecting 38 entries left, got %d", m.Len())
+	}
+
+	b := NewLRUCache[int, int](10)
+	b.Set(1, 1, time.Time{})
+	if n := b.DelPrefix(""); n != 0 {
+		t.Error("expecting no deletion of non string keys")
+	}
+
+	type userID string
+	c := NewLRUCache[userID, int](10)
+	c.Set("user:1", 1, time.Time{})
+	c.Set("admin:1", 1, time.Time{})
+	if n := c.DelPrefix("user:"); n != 1 {
+		t.Errorf("expecting named string keys to match, got %d", n)
+	}
+}
@@ -0,0 +1,109 @@
+// Copyright (c) 2013 CloudFlare, Inc.
+
+package lrucache
+
+import (
+	"iter"
+)
+
+// All returns an iterator over keys and values of the cache, from the
+// most to the least recently used (or valuable, for other policies).
+// Entries are copied under the lock when the iteration starts and
+// yielded after it's released, so the loop body is free to use the
+// cache. Changes made meanwhile aren't seen. LRU scores aren't
+// modified.
+func (b *LRUCache[K, T]) All() iter.Seq2[K, T] {
+	return func(yield func(K, T) bool) {
+		records := b.records(nil)
+		for i := len(records) - 1; i >= 0; i-- {
+			if !yield(records[i].key, records[i].value) {
+				return
+			}
+		}
+	}
+}
+
+// Backward is like All, but goes from the least to the most recently
+// used entry, the order in which they would be evicted.
+func (b *LRUCache[K, T]) Backward() iter.Seq2[K, T] {
+	return func(yield func(K, T) bool) {
+		for _, r := range b.records(nil) {
+			if !yield(r.key, r.value) {
+				return
+			}
+		}
+	}
+}
+
+// Keys returns an iterator over keys of the cache in the order of All.
+func (b *LRUCache[K, T]) Keys() iter.Seq[K] {
+	return keys(b.All())
+}
+
+// Values returns an iterator over values of the cache in the order of
+// All.
+func (b *LRUCache[K, T]) Values() iter.Seq[T] {
+	return values(b.All())
+}
+
+// All returns an iterator over keys and values of all the buckets.
+// Buckets are visited one after another, each is copied under its
+// lock when reached, see LRUCache.All. There is no recency order
+// across buckets.
+func (m *MultiLRUCache[K, T]) All() iter.Seq2[K, T] {
+	return func(yield func(K, T) bool) {
+		for _, c := range m.cache {
+			for k, v := range c.All() {
+				if !yield(k, v) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// Backward is like All, but goes from the least to the most recently
+// used entry of every bucket.
+func (m *MultiLRUCache[K, T]) Backward() iter.Seq2[K, T] {
+	return func(yield func(K, T) bool) {
+		for _, c := range m.cache {
+			for k, v := range c.Backward() {
+				if !yield(k, v) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// Keys returns an iterator over keys of all the buckets in the order
+// of All.
+func (m *MultiLRUCache[K, T]) Keys() iter.Seq[K] {
+	return keys(m.All())
+}
+
+// Values returns an iterator over values of all the buckets in the
+// order of All.
+func (m *MultiLRUCache[K, T]) Values() iter.Seq[T] {
+	return values(m.All())
+}
+
+func keys[K, T any](all iter.Seq2[K, T]) iter.Seq[K] {
+	return func(yield func(K) bool) {
+		for k := range all {
+			if !yield(k) {
+				return
+			}
+		}
+	}
+}
+
+func values[K, T any](all iter.Seq2[K, T]) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for _, v := range all {
+			if !yield(v) {
+				return
+			}
+		}
+	}
+}
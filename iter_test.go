@@ -0,0 +1,69 @@
+package lrucache
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestIter(t *testing.T) {
+	t.Parallel()
+	b := NewLRUCache[string, int](4)
+
+	b.Set("a", 1, time.Time{})
+	b.Set("b", 2, time.Time{})
+	b.Set("c", 3, time.Time{})
+	b.Get("a")
+
+	if keys := slices.Collect(b.Keys()); !slices.Equal(keys, []string{"a", "c", "b"}) {
+		t.Errorf("unexpected order %v", keys)
+	}
+	if values := slices.Collect(b.Values()); !slices.Equal(values, []int{1, 3, 2}) {
+		t.Errorf("unexpected order %v", values)
+	}
+
+	var backward []string
+	for k, v := range b.Backward() {
+		backward = append(backward, k)
+		// Iterating doesn't hold the lock.
+		b.Set(k, v*10, time.Time{})
+	}
+	if !slices.Equal(backward, []string{"b", "c", "a"}) {
+		t.Errorf("unexpected order %v", backward)
+	}
+
+	for k := range b.All() {
+		if k != "a" {
+			t.Error("expecting most recent entry first")
+		}
+		break
+	}
+	if keys := slices.Collect(b.Keys()); !slices.Equal(keys, []string{"a", "c", "b"}) {
+		t.Errorf("expecting iteration not to change order, got %v", keys)
+	}
+}
+
+func TestMultiLRUIter(t *testing.T) {
+	t.Parallel()
+	m := NewMultiLRUCache[int, int](4, 10)
+	for i := 0; i < 10; i++ {
+		m.Set(i, i, time.Time{})
+	}
+
+	keys := slices.Collect(m.Keys())
+	if len(keys) != m.Len() {
+		t.Error("expecting all the keys")
+	}
+	for k, v := range m.All() {
+		if k != v {
+			t.Error("expecting different value")
+		}
+	}
+	n := 0
+	for range m.Backward() {
+		n++
+	}
+	if n != m.Len() || len(slices.Collect(m.Values())) != n {
+		t.Error("expecting all the entries")
+	}
+}
@@ -0,0 +1,122 @@
+// Copyright (c) 2013 CloudFlare, Inc.
+
+package lrucache
+
+import (
+	"time"
+)
+
+// Number of expired entries the janitor removes per lock acquisition.
+const janitorBatch = 128
+
+// Goroutine calling purge periodically until shut down.
+type janitor struct {
+	stop chan struct{}
+	done chan struct{}
+}
+
+// Start the goroutine. Panics right away rather than in the goroutine
+// if interval isn't positive.
+func startJanitor(interval time.Duration, purge func()) *janitor {
+	if interval <= 0 {
+		panic("lrucache: non-positive janitor interval")
+	}
+	j := &janitor{
+		stop: make(chan struct{}),
+		done: make(chan struct{}),
+	}
+	go func() {
+		defer close(j.done)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				purge()
+			case <-j.stop:
+				return
+			}
+		}
+	}()
+	return j
+}
+
+// Stop the goroutine and wait for it to exit. Nil is a no-op.
+func (j *janitor) shutdown() {
+	if j == nil {
+		return
+	}
+	close(j.stop)
+	<-j.done
+}
+
+// StartJanitor starts a goroutine evicting expired entries every
+// interval. It works in small batches, releasing the lock in between,
+// so it never blocks other users for long. Restarts the janitor if
+// it's already running. Call Stop to release it. Panics if interval
+// isn't positive.
+func (b *LRUCache[K, T]) StartJanitor(interval time.Duration) {
+	j := startJanitor(interval, b.purge)
+
+	b.lock.Lock()
+	j, b.janitor = b.janitor, j
+	b.lock.Unlock()
+	j.shutdown()
+}
+
+// Stop stops the janitor and the refresher and waits for them to
+// exit. Safe to call when they aren't running.
+func (b *LRUCache[K, T]) Stop() {
+	b.lock.Lock()
+	j, r := b.janitor, b.refresher
+	b.janitor, b.refresher = nil, nil
+	b.lock.Unlock()
+	j.shutdown()
+	r.shutdown()
+}
+
+// Evict all the expired entries, janitorBatch at a time.
+func (b *LRUCache[K, T]) purge() {
+	now := b.clock.Now()
+	for {
+		b.lock.Lock()
+		n := b.expireEntries(now, janitorBatch)
+		n += b.reclaimStale(janitorBatch - n)
+		b.unlock()
+		if n < janitorBatch {
+			return
+		}
+	}
+}
+
+// StartJanitor starts a single goroutine evicting expired entries of
+// every bucket, see LRUCache.StartJanitor. Panics if interval isn't
+// positive.
+func (m *MultiLRUCache[K, T]) StartJanitor(interval time.Duration) {
+	j := startJanitor(interval, func() {
+		for _, c := range m.cache {
+			c.purge()
+		}
+	})
+
+	m.janitorLock.Lock()
+	j, m.janitor = m.janitor, j
+	m.janitorLock.Unlock()
+	j.shutdown()
+}
+
+// Stop stops the janitor and the refresher and waits for them to
+// exit.
+func (m *MultiLRUCache[K, T]) Stop() {
+	m.janitorLock.Lock()
+	for _, c := range m.cache {
+		c.lock.Lock()
+		c.refresher = nil
+		c.lock.Unlock()
+	}
+	j, r := m.janitor, m.refresher
+	m.janitor, m.refresher = nil, nil
+	m.janitorLock.Unlock()
+	j.shutdown()
+	r.shutdown()
+}
@@ -0,0 +1,72 @@
+package lrucache
+
+import (
+	"testing"
+	"time"
+)
+
+func TestJanitor(t *testing.T) {
+	t.Parallel()
+	b := NewLRUCache[int, int](1000)
+
+	past := time.Now().Add(-time.Second)
+	for i := 0; i < 1000; i++ {
+		expire := past
+		if i%2 == 0 {
+			expire = time.Time{}
+		}
+		b.Set(i, i, expire)
+	}
+
+	b.StartJanitor(time.Millisecond)
+	b.StartJanitor(time.Millisecond)
+	deadline := time.Now().Add(time.Second)
+	for b.Len() != 500 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	b.Stop()
+	b.Stop()
+
+	if b.Len() != 500 {
+		t.Errorf("expecting expired entries to be purged, %d left", b.Len())
+	}
+	if b.Stats().Evicted(EvictExpired) != 500 {
+		t.Error("expecting different number of expirations")
+	}
+}
+
+func TestMultiLRUJanitor(t *testing.T) {
+	t.Parallel()
+	m := NewMultiLRUCache[int, int](4, 100)
+
+	past := time.Now().Add(-time.Second)
+	for i := 0; i < 100; i++ {
+		m.Set(i, i, past)
+	}
+
+	m.StartJanitor(time.Millisecond)
+	deadline := time.Now().Add(time.Second)
+	for m.Len() != 0 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	m.Stop()
+
+	if m.Len() != 0 {
+		t.Error("expecting expired entries to be purged")
+	}
+}
+
+func TestJanitorInterval(t *testing.T) {
+	t.Parallel()
+	b := NewLRUCache[int, int](10)
+	m := NewMultiLRUCache[int, int](2, 10)
+
+	if rec(func() { b.StartJanitor(0) }) != 1 {
+		t.Error("expecting panic")
+	}
+	if rec(func() { m.StartJanitor(-time.Second) }) != 1 {
+		t.Error("expecting panic")
+	}
+	b.Stop()
+	m.Stop()
+}
@@ -0,0 +1,114 @@
+// Copyright (c) 2013 CloudFlare, Inc.
+
+package lrucache
+
+// Entries sharing the same access count. Buckets are linked in the
+// order of increasing count, so the least frequently used entries are
+// always in the first one. All operations are O(1).
+type lfuBucket[K comparable, T any] struct {
+	hits       uint64
+	entries    list[K, T] // ordered by decreasing recency of use
+	prev, next *lfuBucket[K, T]
+}
+
+type lfuPolicy[K comparable, T any] struct {
+	buckets map[uint64]*lfuBucket[K, T]
+	head    *lfuBucket[K, T] // bucket with the lowest count
+	free    []*lfuBucket[K, T]
+	used    int
+}
+
+func newLFUPolicy[K comparable, T any]() *lfuPolicy[K, T] {
+	return &lfuPolicy[K, T]{buckets: make(map[uint64]*lfuBucket[K, T])}
+}
+
+// Get the bucket for a count, creating it after prev if it doesn't
+// exist. prev is nil for the head.
+func (p *lfuPolicy[K, T]) bucket(hits uint64, prev *lfuBucket[K, T]) *lfuBucket[K, T] {
+	if bu := p.buckets[hits]; bu != nil {
+		return bu
+	}
+
+	var bu *lfuBucket[K, T]
+	if n := len(p.free); n > 0 {
+		bu, p.free = p.free[n-1], p.free[:n-1]
+	} else {
+		bu = &lfuBucket[K, T]{}
+		bu.entries.Init()
+	}
+	bu.hits = hits
+	bu.prev = prev
+	if prev == nil {
+		bu.next = p.head
+		p.head = bu
+	} else {
+		bu.next = prev.next
+		prev.next = bu
+	}
+	if bu.next != nil {
+		bu.next.prev = bu
+	}
+	p.buckets[hits] = bu
+	return bu
+}
+
+// Unlink a bucket if it's empty, keeping it around for reuse.
+func (p *lfuPolicy[K, T]) release(bu *lfuBucket[K, T]) {
+	if bu.entries.Len() > 0 {
+		return
+	}
+	if bu.prev == nil {
+		p.head = bu.next
+	} else {
+		bu.prev.next = bu.next
+	}
+	if bu.next != nil {
+		bu.next.prev = bu.prev
+	}
+	bu.prev, bu.next = nil, nil
+	delete(p.buckets, bu.hits)
+	p.free = append(p.free, bu)
+}
+
+func (p *lfuPolicy[K, T]) add(e *entry[K, T]) {
+	e.hits = 1
+	p.bucket(e.hits, nil).entries.PushElementFront(&e.element)
+	p.used++
+}
+
+func (p *lfuPolicy[K, T]) touch(e *entry[K, T]) {
+	bu := p.buckets[e.hits]
+	bu.entries.Remove(&e.element)
+	e.hits++
+	p.bucket(e.hits, bu).entries.PushElementFront(&e.element)
+	p.release(bu)
+}
+
+func (p *lfuPolicy[K, T]) remove(e *entry[K, T], reason EvictReason) {
+	bu := p.buckets[e.hits]
+	bu.entries.Remove(&e.element)
+	p.release(bu)
+	e.hits = 0
+	p.used--
+}
+
+func (p *lfuPolicy[K, T]) victim() *entry[K, T] {
+	if p.head == nil {
+		return nil
+	}
+	return p.head.entries.Back().Value
+}
+
+func (p *lfuPolicy[K, T]) len() int {
+	return p.used
+}
+
+func (p *lfuPolicy[K, T]) resize(capacity int) {}
+
+func (p *lfuPolicy[K, T]) walk(fn func(e *entry[K, T]) bool) {
+	for bu := p.head; bu != nil; bu = bu.next {
+		if !bu.entries.walkBackward(fn) {
+			return
+		}
+	}
+}
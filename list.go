@@ -45,23 +45,23 @@ OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
 package lrucache
 
 // Element is an element of a linked list.
-type element[T any] struct {
+type element[K comparable, T any] struct {
 	// Next and previous pointers in the doubly-linked list of elements.
 	// To simplify the implementation, internally a list l is implemented
 	// as a ring, such that &l.root is both the next element of the last
 	// list element (l.Back()) and the previous element of the first list
 	// element (l.Front()).
-	next, prev *element[T]
+	next, prev *element[K, T]
 
 	// The list to which this element belongs.
-	list *list[T]
+	list *list[K, T]
 
 	// The value stored with this element.
-	Value *entry[T]
+	Value *entry[K, T]
 }
 
 // Next returns the next list element or nil.
-func (e *element[T]) Next() *element[T] {
+func (e *element[K, T]) Next() *element[K, T] {
 	if p := e.next; e.list != nil && p != &e.list.root {
 		return p
 	}
@@ -69,7 +69,7 @@ func (e *element[T]) Next() *element[T] {
 }
 
 // Prev returns the previous list element or nil.
-func (e *element[T]) Prev() *element[T] {
+func (e *element[K, T]) Prev() *element[K, T] {
 	if p := e.prev; e.list != nil && p != &e.list.root {
 		return p
 	}
@@ -78,13 +78,13 @@ func (e *element[T]) Prev() *element[T] {
 
 // List represents a doubly linked list.
 // The zero value for List is an empty list ready to use.
-type list[T any] struct {
-	root element[T] // sentinel list element, only &root, root.prev, and root.next are used
-	len  int        // current list length excluding (this) sentinel element
+type list[K comparable, T any] struct {
+	root element[K, T] // sentinel list element, only &root, root.prev, and root.next are used
+	len  int           // current list length excluding (this) sentinel element
 }
 
 // Init initializes or clears list l.
-func (l *list[T]) Init() *list[T] {
+func (l *list[K, T]) Init() *list[K, T] {
 	l.root.next = &l.root
 	l.root.prev = &l.root
 	l.len = 0
@@ -92,14 +92,14 @@ func (l *list[T]) Init() *list[T] {
 }
 
 // New returns an initialized list.
-// func New() *list[T] { return new(list).init() }
+// func New() *list[K, T] { return new(list).init() }
 
 // Len returns the number of elements of list l.
 // The complexity is O(1).
-func (l *list[T]) Len() int { return l.len }
+func (l *list[K, T]) Len() int { return l.len }
 
 // Front returns the first element of list l or nil
-func (l *list[T]) Front() *element[T] {
+func (l *list[K, T]) Front() *element[K, T] {
 	if l.len == 0 {
 		return nil
 	}
@@ -107,7 +107,7 @@ func (l *list[T]) Front() *element[T] {
 }
 
 // Back returns the last element of list l or nil.
-func (l *list[T]) Back() *element[T] {
+func (l *list[K, T]) Back() *element[K, T] {
 	if l.len == 0 {
 		return nil
 	}
@@ -115,7 +115,7 @@ func (l *list[T]) Back() *element[T] {
 }
 
 // insert inserts e after at, increments l.len, and returns e.
-func (l *list[T]) insert(e, at *element[T]) *element[T] {
+func (l *list[K, T]) insert(e, at *element[K, T]) *element[K, T] {
 	n := at.next
 	at.next = e
 	e.prev = at
@@ -127,12 +127,12 @@ func (l *list[T]) insert(e, at *element[T]) *element[T] {
 }
 
 // insertValue is a convenience wrapper for insert(&Element{Value: v}, at).
-func (l *list[T]) insertValue(v T, at *element[T]) *element[T] {
-	return l.insert(&element[T]{Value: &entry[T]{value: v}}, at)
+func (l *list[K, T]) insertValue(v T, at *element[K, T]) *element[K, T] {
+	return l.insert(&element[K, T]{Value: &entry[K, T]{value: v}}, at)
 }
 
 // remove removes e from its list, decrements l.len, and returns e.
-func (l *list[T]) remove(e *element[T]) *element[T] {
+func (l *list[K, T]) remove(e *element[K, T]) *element[K, T] {
 	e.prev.next = e.next
 	e.next.prev = e.prev
 	e.next = nil // avoid memory leaks
@@ -144,7 +144,7 @@ func (l *list[T]) remove(e *element[T]) *element[T] {
 
 // Remove removes e from l if e is an element of list l.
 // It returns the element value e.Value.
-func (l *list[T]) Remove(e *element[T]) T {
+func (l *list[K, T]) Remove(e *element[K, T]) T {
 	if e.list == l {
 		// if e.list == l, l must have been initialized when e was inserted
 		// in l or l == nil (e is a zero Element) and l.remove will crash
@@ -154,18 +154,18 @@ func (l *list[T]) Remove(e *element[T]) T {
 }
 
 // PushFront inserts a new element e with value v at the front of list l and returns e.
-func (l *list[T]) PushFront(v T) *element[T] {
+func (l *list[K, T]) PushFront(v T) *element[K, T] {
 	return l.insertValue(v, &l.root)
 }
 
 // PushBack inserts a new element e with value v at the back of list l and returns e.
-func (l *list[T]) PushBack(v T) *element[T] {
+func (l *list[K, T]) PushBack(v T) *element[K, T] {
 	return l.insertValue(v, l.root.prev)
 }
 
 // InsertBefore inserts a new element e with value v immediately before mark and returns e.
 // If mark is not an element of l, the list is not modified.
-func (l *list[T]) InsertBefore(v T, mark *element[T]) *element[T] {
+func (l *list[K, T]) InsertBefore(v T, mark *element[K, T]) *element[K, T] {
 	if mark.list != l {
 		return nil
 	}
@@ -175,7 +175,7 @@ func (l *list[T]) InsertBefore(v T, mark *element[T]) *element[T] {
 
 // InsertAfter inserts a new element e with value v immediately after mark and returns e.
 // If mark is not an element of l, the list is not modified.
-func (l *list[T]) InsertAfter(v T, mark *element[T]) *element[T] {
+func (l *list[K, T]) InsertAfter(v T, mark *element[K, T]) *element[K, T] {
 	if mark.list != l {
 		return nil
 	}
@@ -185,7 +185,7 @@ func (l *list[T]) InsertAfter(v T, mark *element[T]) *element[T] {
 
 // MoveToFront moves element e to the front of list l.
 // If e is not an element of l, the list is not modified.
-func (l *list[T]) MoveToFront(e *element[T]) {
+func (l *list[K, T]) MoveToFront(e *element[K, T]) {
 	if e.list != l || l.root.next == e {
 		return
 	}
@@ -195,7 +195,7 @@ func (l *list[T]) MoveToFront(e *element[T]) {
 
 // MoveToBack moves element e to the back of list l.
 // If e is not an element of l, the list is not modified.
-func (l *list[T]) MoveToBack(e *element[T]) {
+func (l *list[K, T]) MoveToBack(e *element[K, T]) {
 	if e.list != l || l.root.prev == e {
 		return
 	}
@@ -205,7 +205,7 @@ func (l *list[T]) MoveToBack(e *element[T]) {
 
 // MoveBefore moves element e to its new position before mark.
 // If e is not an element of l, or e == mark, the list is not modified.
-func (l *list[T]) MoveBefore(e, mark *element[T]) {
+func (l *list[K, T]) MoveBefore(e, mark *element[K, T]) {
 	if e.list != l || e == mark {
 		return
 	}
@@ -214,7 +214,7 @@ func (l *list[T]) MoveBefore(e, mark *element[T]) {
 
 // MoveAfter moves element e to its new position after mark.
 // If e is not an element of l, or e == mark, the list is not modified.
-func (l *list[T]) MoveAfter(e, mark *element[T]) {
+func (l *list[K, T]) MoveAfter(e, mark *element[K, T]) {
 	if e.list != l || e == mark {
 		return
 	}
@@ -223,7 +223,7 @@ func (l *list[T]) MoveAfter(e, mark *element[T]) {
 
 // PushBackList inserts a copy of an other list at the back of list l.
 // The lists l and other may be the same.
-func (l *list[T]) PushBackList(other *list[T]) {
+func (l *list[K, T]) PushBackList(other *list[K, T]) {
 	for i, e := other.Len(), other.Front(); i > 0; i, e = i-1, e.Next() {
 		l.insertValue(e.Value.value, l.root.prev)
 	}
@@ -231,7 +231,7 @@ func (l *list[T]) PushBackList(other *list[T]) {
 
 // PushFrontList inserts a copy of an other list at the front of list l.
 // The lists l and other may be the same.
-func (l *list[T]) PushFrontList(other *list[T]) {
+func (l *list[K, T]) PushFrontList(other *list[K, T]) {
 	for i, e := other.Len(), other.Back(); i > 0; i, e = i-1, e.Prev() {
 		l.insertValue(e.Value.value, &l.root)
 	}
@@ -4,22 +4,39 @@
 
 package lrucache
 
-func (l *list[T]) PushElementFront(e *element[T]) *element[T] {
+func (l *list[K, T]) PushElementFront(e *element[K, T]) *element[K, T] {
 	return l.insert(e, &l.root)
 }
 
-func (l *list[T]) PushElementBack(e *element[T]) *element[T] {
+func (l *list[K, T]) PushElementBack(e *element[K, T]) *element[K, T] {
 	return l.insert(e, l.root.prev)
 }
 
-func (l *list[T]) PopElementFront() *element[T] {
+func (l *list[K, T]) PopElementFront() *element[K, T] {
 	el := l.Front()
 	l.Remove(el)
 	return el
 }
 
-func (l *list[T]) PopFront() interface{} {
+func (l *list[K, T]) PopFront() interface{} {
 	el := l.Front()
 	l.Remove(el)
 	return el.Value
 }
+
+func (l *list[K, T]) PopElementBack() *element[K, T] {
+	el := l.Back()
+	l.Remove(el)
+	return el
+}
+
+// Call fn for every entry from back to front until it returns false.
+// Returns false if stopped early.
+func (l *list[K, T]) walkBackward(fn func(e *entry[K, T]) bool) bool {
+	for el := l.Back(); el != nil; el = el.Prev() {
+		if !fn(el.Value) {
+			return false
+		}
+	}
+	return true
+}
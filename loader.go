@@ -0,0 +1,80 @@
+// Copyright (c) 2013 CloudFlare, Inc.
+
+package lrucache
+
+import (
+	"context"
+	"time"
+)
+
+// LoaderFunc fetches a value missing from the cache together with its
+// expiry time. Zero expiry means the value never expires.
+type LoaderFunc[T any] func(ctx context.Context) (value T, expire time.Time, err error)
+
+// A load in flight. Callers asking for the same key wait for done to
+// be closed and then read value and err.
+type loadCall[T any] struct {
+	done  chan struct{}
+	value T
+	err   error
+}
+
+// GetOrLoad gets a key from the cache, making sure it's not stale. On
+// a miss it calls loader and stores the result. Concurrent callers
+// asking for the same key share a single loader call. Errors are
+// returned to every waiting caller but never cached.
+//
+// The loader runs detached from the callers' cancellation, so a
+// caller giving up early with ctx.Err() doesn't fail the load for the
+// others.
+func (b *LRUCache[K, T]) GetOrLoad(ctx context.Context, key K, loader LoaderFunc[T]) (T, error) {
+	b.lock.Lock()
+	if value, ok := b.getNotStale(key, b.clock.Now()); ok {
+		b.unlock()
+		return value, nil
+	}
+
+	call, ok := b.loads[key]
+	if !ok {
+		call = &loadCall[T]{done: make(chan struct{})}
+		if b.loads == nil {
+			b.loads = make(map[K]*loadCall[T])
+		}
+		b.loads[key] = call
+		go b.load(context.WithoutCancel(ctx), key, call, loader)
+	}
+	b.unlock()
+
+	select {
+	case <-call.done:
+		return call.value, call.err
+	case <-ctx.Done():
+		var t T
+		return t, ctx.Err()
+	}
+}
+
+func (b *LRUCache[K, T]) load(ctx context.Context, key K, call *loadCall[T], loader LoaderFunc[T]) {
+	start := b.clock.Now()
+	value, expire, err := loader(ctx)
+	if err == nil {
+		b.SetWithCost(key, value, expire, b.clock.Now().Sub(start))
+	}
+	b.finishLoad(key, call, value, err)
+}
+
+// Hand the result of a load to the callers waiting for it.
+func (b *LRUCache[K, T]) finishLoad(key K, call *loadCall[T], value T, err error) {
+	b.lock.Lock()
+	delete(b.loads, key)
+	b.lock.Unlock()
+
+	call.value, call.err = value, err
+	close(call.done)
+}
+
+// GetOrLoad gets a key from the cache or loads it, see
+// LRUCache.GetOrLoad.
+func (m *MultiLRUCache[K, T]) GetOrLoad(ctx context.Context, key K, loader LoaderFunc[T]) (T, error) {
+	return m.cache[m.bucketNo(key)].GetOrLoad(ctx, key, loader)
+}
@@ -0,0 +1,88 @@
+package lrucache
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestGetOrLoad(t *testing.T) {
+	t.Parallel()
+	b := NewLRUCache[string, string](3)
+	ctx := context.Background()
+
+	var calls atomic.Int32
+	release := make(chan struct{})
+	loader := func(ctx context.Context) (string, time.Time, error) {
+		calls.Add(1)
+		<-release
+		return "va", time.Time{}, nil
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if v, err := b.GetOrLoad(ctx, "a", loader); v != "va" || err != nil {
+				t.Error("expecting loaded value")
+			}
+		}()
+	}
+	time.Sleep(10 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if calls.Load() != 1 {
+		t.Errorf("expecting a single load, got %d", calls.Load())
+	}
+	if v, ok := b.GetNotStale("a"); !ok || v != "va" {
+		t.Error("expecting hit")
+	}
+}
+
+func TestGetOrLoadError(t *testing.T) {
+	t.Parallel()
+	b := NewLRUCache[string, string](3)
+	ctx := context.Background()
+
+	fail := errors.New("fail")
+	_, err := b.GetOrLoad(ctx, "a", func(ctx context.Context) (string, time.Time, error) {
+		return "", time.Time{}, fail
+	})
+	if err != fail {
+		t.Error("expecting error")
+	}
+	if _, ok := b.Get("a"); ok {
+		t.Error("expecting error not to be cached")
+	}
+}
+
+func TestGetOrLoadCancel(t *testing.T) {
+	t.Parallel()
+	b := NewLRUCache[string, string](3)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	release := make(chan struct{})
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		cancel()
+	}()
+	_, err := b.GetOrLoad(ctx, "a", func(ctx context.Context) (string, time.Time, error) {
+		<-release
+		return "va", time.Time{}, ctx.Err()
+	})
+	if err != context.Canceled {
+		t.Error("expecting cancellation")
+	}
+
+	// The load itself carries on for other callers.
+	close(release)
+	v, err := b.GetOrLoad(context.Background(), "a", nil)
+	if v != "va" || err != nil {
+		t.Error("expecting loaded value")
+	}
+}
@@ -4,66 +4,188 @@ package lrucache
 
 import (
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
 // Every element in the cache is linked to three data structures:
-// Table map, PriorityQueue heap ordered by expiry and a LruList list
-// ordered by decreasing popularity.
-type entry[T any] struct {
-	element element[T] // list element. value is a pointer to this entry
-	key     string     // key is a key!
-	value   T          //
-	expire  time.Time  // time when the item is expired. it's okay to be stale.
-	index   int        // index for priority queue needs. -1 if entry is free
+// Table map, PriorityQueue heap ordered by expiry and the eviction
+// policy ordering entries by decreasing popularity.
+type entry[K comparable, T any] struct {
+	element element[K, T] // list element. value is a pointer to this entry
+	key     K             // key is a key!
+	value   T             //
+	expire  time.Time     // time when the item is expired. it's okay to be stale.
+	index   int           // index for priority queue needs. -1 if entry is free
+	weight  uint64        // cost of the entry, see WithMaxWeight
+	hits    uint64        // access count kept by the eviction policy
+	pinned  bool          // in pinned list instead of policy, see Pin
+	ext     *entryExt     // nil until one of its fields is needed
+	meta    *entryMeta    // nil unless WithEntryMetadata
+}
+
+// Fields of an entry most caches never use, kept apart so that entries
+// stay small. Allocated on first use and kept with the entry after
+// that, or next to the entries with WithGenerations.
+type entryExt struct {
+	idle     time.Duration // expiry is pushed back by this much on reads, see SetSliding
+	deadline time.Time     // expiry is never pushed back past it
+	cost     time.Duration // time it took to compute value, see WithEarlyExpiration
+	tags     []string      // sorted, see SetWithTags
+	gen      uint64        // generation the entry was stored in, see WithGenerations
+}
+
+// Extension fields of the entry, allocated if needed.
+func (e *entry[K, T]) extend() *entryExt {
+	if e.ext == nil {
+		e.ext = new(entryExt)
+	}
+	return e.ext
+}
+
+// Is the entry expired at a given time. Entries with zero expiry
+// never expire.
+func (e *entry[K, T]) expired(now time.Time) bool {
+	return !e.expire.IsZero() && e.expire.Before(now)
 }
 
 // LRUCache data structure. Never dereference it or copy it by
 // value. Always use it through a pointer.
-type LRUCache[T any] struct {
+type LRUCache[K comparable, T any] struct {
 	lock          sync.Mutex
-	table         map[string]*entry[T] // all entries in table must be in lruList
-	priorityQueue priorityQueue[T]     // some elements from table may be in priorityQueue
-	lruList       list[T]              // every entry is either used and resides in lruList
-	freeList      list[T]              // or free and is linked to freeList
+	addr          *LRUCache[K, T]      // of the receiver, to detect copies by value
+	table         map[K]*entry[K, T]   // all entries in table must be in policy
+	priorityQueue priorityQueue[K, T]  // some elements from table may be in priorityQueue
+	policy        evictionPolicy[K, T] // every entry is either used and tracked by policy
+	freeList      list[K, T]           // or free and is linked to freeList
+	pinned        list[K, T]           // or pinned, see Pin
+	pinnedWeight  uint64               // weight of pinned entries not cleared
+	maxPinned     int                  // 0 if unlimited, see WithMaxPinned
+	metadata      bool                 // see WithEntryMetadata
 
 	ExpireGracePeriod time.Duration // time after an expired entry is purged from cache (unless pushed out of LRU)
+
+	// OnEvict, when set, is called for every entry leaving the
+	// cache. It runs after the lock is released, so it may block or
+	// use the cache. Set it before the cache is used.
+	OnEvict func(key K, value T, reason EvictReason)
+	evicted []eviction[K, T] // removed entries pending for OnEvict
+
+	// Sizer, when set, computes the weight of entries stored by Set.
+	// Otherwise every entry weighs 1. A zero weight counts as 1. Set
+	// it before the cache is used.
+	Sizer     func(key K, value T) uint64
+	weight    uint64 // total weight of used entries
+	maxWeight uint64 // 0 when the cache is bounded by number of entries
+
+	counters  counters
+	loads     map[K]*loadCall[T]                   // loads in flight, see GetOrLoad
+	tags      map[string]map[*entry[K, T]]struct{} // entries by tag, see SetWithTags
+	janitor   *janitor                             // see StartJanitor
+	refresher *refresher[K, T]                     // see StartRefresher
+	clock     Clock
+	earlyBeta float64 // see WithEarlyExpiration
+	ttlJitter float64 // see WithTTLJitter
+
+	gen        *atomic.Uint64  // current generation, nil unless WithGenerations
+	seenGen    uint64          // generation as of the last sync
+	staleLen   int             // number of entries from older generations
+	stalePins  int             // number of pinned ones among them
+	blocks     [][]entry[K, T] // all the entries, scanned for stale ones
+	spare      list[K, T]      // entries released by Resize, reused since blocks keeps them
+	staleBlock int             // position of the scan in blocks
+	staleIdx   int
 }
 
 // Initialize the LRU cache instance. O(capacity)
-func (b *LRUCache[T]) init(capacity uint) {
-	b.table = make(map[string]*entry[T], capacity)
-	b.priorityQueue = make([]*entry[T], 0, capacity)
-	b.lruList.Init()
+func (b *LRUCache[K, T]) init(capacity uint, opts ...Option) {
+	o := newOptions(opts)
+
+	b.addr = b
+	b.table = make(map[K]*entry[K, T], capacity)
+	b.priorityQueue = make([]*entry[K, T], 0, capacity)
+	b.policy = newPolicy[K, T](o.policy, int(capacity))
 	b.freeList.Init()
-	HeapInit[T](&b.priorityQueue)
+	b.pinned.Init()
+	b.spare.Init()
+	b.maxPinned = o.maxPinned
+	b.metadata = o.metadata
+	HeapInit[K, T](&b.priorityQueue)
+	b.maxWeight = o.maxWeight
+	b.clock = o.clock
+	b.earlyBeta = o.earlyBeta
+	b.ttlJitter = o.ttlJitter
+	if o.generations {
+		b.gen = o.gen
+		if b.gen == nil {
+			b.gen = new(atomic.Uint64)
+		}
+	}
+	b.allocEntries(capacity)
+}
+
+// Add n free entries to the cache, spare ones first. O(n)
+func (b *LRUCache[K, T]) allocEntries(n uint) {
+	for ; n > 0 && b.spare.Len() > 0; n-- {
+		b.freeList.PushElementBack(b.spare.PopElementFront())
+	}
+	if n == 0 {
+		return
+	}
 
 	// Reserve all the entries in one giant continous block of memory
-	arrayOfEntries := make([]entry[T], capacity)
-	for i := uint(0); i < capacity; i++ {
+	arrayOfEntries := make([]entry[K, T], n)
+	var metas []entryMeta
+	if b.metadata {
+		metas = make([]entryMeta, n)
+	}
+	// Every entry has a generation.
+	var exts []entryExt
+	if b.gen != nil {
+		exts = make([]entryExt, n)
+	}
+	for i := uint(0); i < n; i++ {
 		e := &arrayOfEntries[i]
 		e.element.Value = e
 		e.index = -1
+		if metas != nil {
+			e.meta = &metas[i]
+		}
+		if exts != nil {
+			e.ext = &exts[i]
+		}
 		b.freeList.PushElementBack(&e.element)
 	}
+	if b.gen != nil {
+		b.blocks = append(b.blocks, arrayOfEntries)
+	}
 }
 
 // Create new LRU cache instance. Allocate all the needed memory. O(capacity)
-func NewLRUCache[T any](capacity uint) *LRUCache[T] {
-	b := &LRUCache[T]{}
-	b.init(capacity)
+// Despite the name, the eviction policy can be changed with WithPolicy.
+func NewLRUCache[K comparable, T any](capacity uint, opts ...Option) *LRUCache[K, T] {
+	b := &LRUCache[K, T]{}
+	b.init(capacity, opts...)
 	return b
 }
 
+// StringLRUCache is an LRUCache keyed by strings.
+type StringLRUCache[T any] = LRUCache[string, T]
+
+// Create new string keyed LRU cache instance. O(capacity)
+func NewStringLRUCache[T any](capacity uint, opts ...Option) *StringLRUCache[T] {
+	return NewLRUCache[string, T](capacity, opts...)
+}
+
 // Give me the entry with lowest expiry field if it's before now.
-func (b *LRUCache[T]) expiredEntry(now time.Time) *entry[T] {
+func (b *LRUCache[K, T]) expiredEntry(now time.Time) *entry[K, T] {
 	if len(b.priorityQueue) == 0 {
 		return nil
 	}
 
 	if now.IsZero() {
 		// Fill it only when actually used.
-		now = time.Now()
+		now = b.clock.Now()
 	}
 
 	if e := b.priorityQueue[0]; e.expire.Before(now) {
@@ -73,45 +195,142 @@ func (b *LRUCache[T]) expiredEntry(now time.Time) *entry[T] {
 }
 
 // Give me the least used entry.
-func (b *LRUCache[T]) leastUsedEntry() *entry[T] {
-	return b.lruList.Back().Value
+func (b *LRUCache[K, T]) leastUsedEntry() *entry[K, T] {
+	return b.policy.victim()
 }
 
-func (b *LRUCache[T]) freeSomeEntry(now time.Time) (e *entry[T], used bool) {
-	if b.freeList.Len() > 0 {
-		return b.freeList.Front().Value, false
+// Evict a cleared entry, an expired one or, if there is none, the
+// least used one. Fails rather than evict keep.
+func (b *LRUCache[K, T]) evictEntry(now time.Time, keep *entry[K, T]) bool {
+	if b.reclaimStale(1) == 1 {
+		return true
+	}
+	if e := b.expiredEntry(now); e != nil {
+		if e == keep {
+			return false
+		}
+		b.removeEntry(e, EvictExpired)
+		return true
 	}
 
-	e = b.expiredEntry(now)
-	if e != nil {
-		return e, true
+	e := b.leastUsedEntry()
+	if e == nil || e == keep {
+		return false
 	}
 
-	if b.lruList.Len() == 0 {
-		return nil, false
+	b.removeEntry(e, EvictCapacity)
+	return true
+}
+
+// Give me a free entry, evicting one if there is no free slot left.
+// Caches bounded by weight grow instead, their weight is kept in
+// check by makeRoom.
+func (b *LRUCache[K, T]) freeSomeEntry(now time.Time) *entry[K, T] {
+	if b.freeList.Len() == 0 && b.reclaimStale(1) == 0 {
+		if b.maxWeight > 0 {
+			b.allocEntries(uint(b.used()/4 + 1))
+		} else if !b.evictEntry(now, nil) {
+			return nil
+		}
 	}
+	return b.freeList.Front().Value
+}
 
-	return b.leastUsedEntry(), true
+// Evict entries until an entry of a given weight fits under
+// maxWeight, in place of keep unless it's nil. Always succeeds for
+// caches bounded by entry count.
+func (b *LRUCache[K, T]) makeRoom(weight uint64, now time.Time, keep *entry[K, T]) bool {
+	if b.maxWeight == 0 {
+		return true
+	}
+	if weight > b.maxWeight {
+		return false
+	}
+	kept := uint64(0)
+	if keep != nil {
+		kept = keep.weight
+	}
+	for b.weight-kept+weight > b.maxWeight {
+		if !b.evictEntry(now, keep) {
+			return false
+		}
+	}
+	return true
+}
+
+// Can an entry of a given weight fit, in place of old unless it's nil,
+// once every entry that isn't pinned is evicted.
+func (b *LRUCache[K, T]) fits(weight uint64, old *entry[K, T]) bool {
+	if b.maxWeight == 0 {
+		return true
+	}
+	pinned := b.pinnedWeight
+	if old != nil && old.pinned {
+		pinned -= old.weight
+	}
+	return pinned+weight <= b.maxWeight
 }
 
-// Move entry from used/lru list to a free list. Clear the entry as well.
-func (b *LRUCache[T]) removeEntry(e *entry[T]) {
-	if e.element.list != &b.lruList {
-		panic("list lruList")
+// Weight of an entry stored by Set.
+func (b *LRUCache[K, T]) weigh(key K, value T) uint64 {
+	if b.Sizer == nil {
+		return 1
+	}
+	return b.Sizer(key, value)
+}
+
+// Move entry from the policy to a free list. Clear the entry as well.
+func (b *LRUCache[K, T]) removeEntry(e *entry[K, T], reason EvictReason) {
+	if b.addr != b {
+		panic("lrucache: LRUCache copied by value")
+	}
+	if e.element.list == &b.freeList {
+		panic("list freeList")
+	}
+
+	stale := b.stale(e)
+	if stale {
+		b.staleLen--
+		if e.pinned {
+			b.stalePins--
+		}
+		reason = EvictCleared
+	}
+	b.counters.evictions[reason].Add(1)
+	if b.OnEvict != nil {
+		b.evicted = append(b.evicted, eviction[K, T]{e.key, e.value, reason})
 	}
 
 	if e.index != -1 {
 		HeapRemove(&b.priorityQueue, e.index)
 	}
-	b.lruList.Remove(&e.element)
+	if e.pinned {
+		b.pinned.Remove(&e.element)
+		e.pinned = false
+		if !stale {
+			b.pinnedWeight -= e.weight
+		}
+	} else {
+		b.policy.remove(e, reason)
+	}
+	b.untag(e)
 	b.freeList.PushElementFront(&e.element)
 	delete(b.table, e.key)
-	e.key = ""
+	b.weight -= e.weight
+	e.weight = 0
+	if x := e.ext; x != nil {
+		x.idle, x.deadline, x.cost = 0, time.Time{}, 0
+	}
+	var k K
+	e.key = k
 	var t T
 	e.value = t
 }
 
-func (b *LRUCache[T]) insertEntry(e *entry[T]) {
+func (b *LRUCache[K, T]) insertEntry(e *entry[K, T]) {
+	if b.addr != b {
+		panic("lrucache: LRUCache copied by value")
+	}
 	if e.element.list != &b.freeList {
 		panic("list freeList")
 	}
@@ -119,139 +338,276 @@ func (b *LRUCache[T]) insertEntry(e *entry[T]) {
 	if !e.expire.IsZero() {
 		HeapPush(&b.priorityQueue, e)
 	}
+	if b.gen != nil {
+		e.ext.gen = b.seenGen
+	}
 	b.freeList.Remove(&e.element)
-	b.lruList.PushElementFront(&e.element)
+	b.policy.add(e)
 	b.table[e.key] = e
+	b.weight += e.weight
 }
 
-func (b *LRUCache[T]) touchEntry(e *entry[T]) {
-	b.lruList.MoveToFront(&e.element)
+// Number of entries in use, tracked by the policy or pinned.
+func (b *LRUCache[K, T]) used() int {
+	return b.policy.len() + b.pinned.Len()
+}
+
+// Record a read of an entry. Extends the expiry of entries with idle
+// timeout and updates metadata, now is filled from the clock only when
+// needed.
+func (b *LRUCache[K, T]) touchEntry(e *entry[K, T], now time.Time) {
+	if !e.pinned {
+		b.policy.touch(e)
+	}
+	x := e.ext
+	sliding := x != nil && x.idle != 0
+	if !sliding && !b.metadata {
+		return
+	}
+
+	if now.IsZero() {
+		now = b.clock.Now()
+	}
+	if b.metadata {
+		e.meta.accessed = now
+		e.meta.accesses++
+	}
+	if !sliding || e.expired(now) {
+		return
+	}
+	e.expire = now.Add(x.idle)
+	if !x.deadline.IsZero() && e.expire.After(x.deadline) {
+		e.expire = x.deadline
+	}
+	HeapFix(&b.priorityQueue, e.index)
 }
 
 // SetNow adds an item to the cache overwriting existing one if it
 // exists. Allows specifing current time required to expire an item
 // when no more slots are used. O(log(n)) if expiry is set, O(1) when
 // clear.
-func (b *LRUCache[T]) SetNow(key string, value T, expire time.Time, now time.Time) {
+func (b *LRUCache[K, T]) SetNow(key K, value T, expire time.Time, now time.Time) {
+	b.SetWeightedNow(key, value, b.weigh(key, value), expire, now)
+}
+
+// Set adds an item to the cache overwriting existing one if it
+// exists. A zero expire means it never expires. O(log(n)) if expiry
+// is set, O(1) when clear.
+func (b *LRUCache[K, T]) Set(key K, value T, expire time.Time) {
+	b.SetNow(key, value, expire, time.Time{})
+}
+
+// SetWeightedNow adds an item of a given weight to the cache
+// overwriting existing one if it exists. Entries are evicted until
+// the weight fits under the limit set by WithMaxWeight, an item
+// heavier than the limit is not stored at all. A zero weight counts
+// as 1, so that the number of entries stays bounded. Allows specifing
+// current time required to expire an item.
+func (b *LRUCache[K, T]) SetWeightedNow(key K, value T, weight uint64, expire time.Time, now time.Time) {
 	b.lock.Lock()
-	defer b.lock.Unlock()
+	defer b.unlock()
 
-	var used bool
+	b.set(key, value, weight, b.jitter(expire, now), now)
+}
 
+// Store an item, see SetWeightedNow. Returns its entry, nil if it
+// wasn't stored, in which case the cache is left as it was. Must be
+// called with the lock held.
+func (b *LRUCache[K, T]) set(key K, value T, weight uint64, expire time.Time, now time.Time) *entry[K, T] {
+	weight = max(weight, 1)
+	b.sync()
 	e := b.table[key]
+	if e != nil && b.stale(e) {
+		// Cleared already, it's as good as missing.
+		b.removeEntry(e, EvictCleared)
+		e = nil
+	}
+	if !b.fits(weight, e) {
+		return nil
+	}
+
+	pinned := false
 	if e != nil {
-		used = true
-	} else {
-		e, used = b.freeSomeEntry(now)
-		if e == nil {
-			return
+		if b.makeRoom(weight, now, e) {
+			b.counters.sets.Add(1)
+			b.replaceEntry(e, value, weight, expire, now)
+			return e
 		}
+		// Nothing but the entry itself is left to evict, store the
+		// item anew.
+		pinned = e.pinned
+		b.counters.overwrites.Add(1)
+		b.removeEntry(e, EvictReplaced)
+	}
+	if !b.makeRoom(weight, now, nil) {
+		return nil
 	}
-	if used {
-		b.removeEntry(e)
+	e = b.freeSomeEntry(now)
+	if e == nil {
+		return nil
 	}
+	b.counters.sets.Add(1)
 
 	e.key = key
 	e.value = value
 	e.expire = expire
+	e.weight = weight
+	b.resetMeta(e, now)
 	b.insertEntry(e)
+	if pinned {
+		b.pin(e)
+	}
+	return e
 }
 
-// Set adds an item to the cache overwriting existing one if it
-// exists. O(log(n)) if expiry is set, O(1) when clear.
-func (b *LRUCache[T]) Set(key string, value T, expire time.Time) {
-	b.SetNow(key, value, expire, time.Time{})
+// Overwrite the value of an entry in place. The policy sees it as a
+// read, so the entry keeps its standing. Must be called with the lock
+// held.
+func (b *LRUCache[K, T]) replaceEntry(e *entry[K, T], value T, weight uint64, expire time.Time, now time.Time) {
+	b.counters.overwrites.Add(1)
+	b.counters.evictions[EvictReplaced].Add(1)
+	if b.OnEvict != nil {
+		b.evicted = append(b.evicted, eviction[K, T]{e.key, e.value, EvictReplaced})
+	}
+
+	b.untag(e)
+	b.weight = b.weight - e.weight + weight
+	if e.pinned {
+		b.pinnedWeight = b.pinnedWeight - e.weight + weight
+	} else {
+		b.policy.touch(e)
+	}
+	e.value = value
+	e.weight = weight
+	if e.ext != nil {
+		e.ext.cost = 0
+	}
+	b.setExpire(e, expire)
+	b.resetMeta(e, now)
+}
+
+// SetWeighted adds an item of a given weight to the cache overwriting
+// existing one if it exists. See SetWeightedNow.
+func (b *LRUCache[K, T]) SetWeighted(key K, value T, weight uint64, expire time.Time) {
+	b.SetWeightedNow(key, value, weight, expire, time.Time{})
 }
 
 // Get a key from the cache, possibly stale. Update its LRU score. O(1)
-func (b *LRUCache[T]) Get(key string) (v T, ok bool) {
+func (b *LRUCache[K, T]) Get(key K) (v T, ok bool) {
 	b.lock.Lock()
 	defer b.lock.Unlock()
 
-	e := b.table[key]
+	e := b.lookup(key)
 	if e == nil {
+		b.counters.misses.Add(1)
 		var t T
 		return t, false
 	}
 
-	b.touchEntry(e)
+	b.counters.hits.Add(1)
+	b.touchEntry(e, time.Time{})
 	return e.value, true
 }
 
 // GetQuiet gets a key from the cache, possibly stale. Don't modify its LRU score. O(1)
-func (b *LRUCache[T]) GetQuiet(key string) (v T, ok bool) {
+func (b *LRUCache[K, T]) GetQuiet(key K) (v T, ok bool) {
 	b.lock.Lock()
 	defer b.lock.Unlock()
 
-	e := b.table[key]
+	e := b.lookup(key)
 
 	if e == nil {
+		b.counters.misses.Add(1)
 		var t T
 		return t, false
 	}
 
+	b.counters.hits.Add(1)
 	return e.value, true
 }
 
 // GetNotStale gets a key from the cache, make sure it's not stale. Update its
-// LRU score. O(log(n)) if the item is expired.
-func (b *LRUCache[T]) GetNotStale(key string) (value T, ok bool) {
-	return b.GetNotStaleNow(key, time.Now())
+// LRU score. Items set with a zero expire are never stale. O(log(n)) if
+// the item is expired.
+func (b *LRUCache[K, T]) GetNotStale(key K) (value T, ok bool) {
+	return b.GetNotStaleNow(key, b.clock.Now())
 }
 
 // GetNotStaleNow gets a key from the cache, make sure it's not stale. Update its
 // LRU score. O(log(n)) if the item is expired.
-func (b *LRUCache[T]) GetNotStaleNow(key string, now time.Time) (value T, ok bool) {
+func (b *LRUCache[K, T]) GetNotStaleNow(key K, now time.Time) (value T, ok bool) {
 	b.lock.Lock()
-	defer b.lock.Unlock()
+	defer b.unlock()
 
-	e := b.table[key]
+	return b.getNotStale(key, now)
+}
+
+// Lookup for GetNotStaleNow. Must be called with the lock held.
+func (b *LRUCache[K, T]) getNotStale(key K, now time.Time) (value T, ok bool) {
+	e := b.lookup(key)
 	var t T
 	if e == nil {
+		b.counters.misses.Add(1)
 		return t, false
 	}
 
-	if e.expire.Before(now) {
+	if e.expired(now) {
+		b.counters.misses.Add(1)
+		b.counters.expirations.Add(1)
 		// Remove entries expired for more than a graceful period
-		if b.ExpireGracePeriod == 0 || e.expire.Sub(now) > b.ExpireGracePeriod {
-			b.removeEntry(e)
+		if b.ExpireGracePeriod == 0 || now.Sub(e.expire) > b.ExpireGracePeriod {
+			b.removeEntry(e, EvictExpired)
 		}
 		return t, false
 	}
+	if b.expiresEarly(e, now) {
+		b.counters.misses.Add(1)
+		return t, false
+	}
 
-	b.touchEntry(e)
+	b.counters.hits.Add(1)
+	b.touchEntry(e, now)
 	return e.value, true
 }
 
 // GetStale gets a key from the cache, possibly stale. Update its LRU
-// score. O(1) always.
-func (b *LRUCache[T]) GetStale(key string) (value T, ok, expired bool) {
-	return b.GetStaleNow(key, time.Now())
+// score. Items set with a zero expire are never reported as expired.
+// O(1) always.
+func (b *LRUCache[K, T]) GetStale(key K) (value T, ok, expired bool) {
+	return b.GetStaleNow(key, b.clock.Now())
 }
 
 // GetStaleNow gets a key from the cache, possibly stale. Update its LRU
-// score. O(1) always.
-func (b *LRUCache[T]) GetStaleNow(key string, now time.Time) (value T, ok, expired bool) {
+// score. O(1) always. Schedules a refresh of stale entries, see
+// StartRefresher.
+func (b *LRUCache[K, T]) GetStaleNow(key K, now time.Time) (value T, ok, expired bool) {
 	b.lock.Lock()
 	defer b.lock.Unlock()
 
-	e := b.table[key]
+	e := b.lookup(key)
 
 	if e == nil {
+		b.counters.misses.Add(1)
 		var t T
 		return t, false, false
 	}
 
-	b.touchEntry(e)
-	return e.value, true, e.expire.Before(now)
+	expired = e.expired(now)
+	b.counters.hits.Add(1)
+	if expired {
+		b.counters.staleHits.Add(1)
+		b.scheduleRefresh(e, now)
+	}
+	b.touchEntry(e, now)
+	return e.value, true, expired
 }
 
 // Del gets and remove a key from the cache. O(log(n)) if the item is using expiry, O(1) otherwise.
-func (b *LRUCache[T]) Del(key string) (v T, ok bool) {
+func (b *LRUCache[K, T]) Del(key K) (v T, ok bool) {
 	b.lock.Lock()
-	defer b.lock.Unlock()
+	defer b.unlock()
 
-	e := b.table[key]
+	e := b.lookup(key)
 
 	if e == nil {
 		var t T
@@ -259,66 +615,99 @@ func (b *LRUCache[T]) Del(key string) (v T, ok bool) {
 	}
 
 	value := e.value
-	b.removeEntry(e)
+	b.removeEntry(e, EvictDeleted)
 	return value, true
 }
 
-// Evict all items from the cache. O(n*log(n))
-func (b *LRUCache[T]) Clear() int {
+// Evict all items from the cache. O(n*log(n)), O(1) with
+// WithGenerations.
+func (b *LRUCache[K, T]) Clear() int {
 	b.lock.Lock()
-	defer b.lock.Unlock()
+	defer b.unlock()
+
+	if b.gen != nil {
+		n := b.len()
+		b.gen.Add(1)
+		return n
+	}
 
 	// First, remove entries that have expiry set
 	l := len(b.priorityQueue)
 	for i := 0; i < l; i++ {
 		// This could be reduced to O(n).
-		b.removeEntry(b.priorityQueue[0])
+		b.removeEntry(b.priorityQueue[0], EvictCleared)
 	}
 
 	// Second, remove all remaining entries
-	r := b.lruList.Len()
+	r := b.policy.len()
 	for i := 0; i < r; i++ {
-		b.removeEntry(b.leastUsedEntry())
+		b.removeEntry(b.leastUsedEntry(), EvictCleared)
+	}
+
+	// Last, the pinned ones
+	p := b.pinned.Len()
+	for i := 0; i < p; i++ {
+		b.removeEntry(b.pinned.Front().Value, EvictCleared)
 	}
-	return l + r
+	return l + r + p
 }
 
 // Evict all the expired items. O(n*log(n))
-func (b *LRUCache[T]) Expire() int {
-	return b.ExpireNow(time.Now())
+func (b *LRUCache[K, T]) Expire() int {
+	return b.ExpireNow(b.clock.Now())
 }
 
 // Evict items that expire before `now`. O(n*log(n))
-func (b *LRUCache[T]) ExpireNow(now time.Time) int {
+func (b *LRUCache[K, T]) ExpireNow(now time.Time) int {
 	b.lock.Lock()
-	defer b.lock.Unlock()
+	defer b.unlock()
+
+	return b.expireEntries(now, 0)
+}
 
+// Evict at most limit items that expire before `now`, all of them if
+// limit is 0. Must be called with the lock held.
+func (b *LRUCache[K, T]) expireEntries(now time.Time, limit int) int {
 	i := 0
-	for {
+	for limit == 0 || i < limit {
 		e := b.expiredEntry(now)
 		if e == nil {
 			break
 		}
-		b.removeEntry(e)
+		b.removeEntry(e, EvictExpired)
 		i += 1
 	}
 	return i
 }
 
 // Number of entries used in the LRU
-func (b *LRUCache[T]) Len() int {
+func (b *LRUCache[K, T]) Len() int {
 	// yes. this stupid thing requires locking
 	b.lock.Lock()
 	defer b.lock.Unlock()
 
-	return b.lruList.Len()
+	return b.len()
 }
 
 // Capacity gets the total capacity of the LRU
-func (b *LRUCache[T]) Capacity() int {
+func (b *LRUCache[K, T]) Capacity() int {
 	// yes. this stupid thing requires locking
 	b.lock.Lock()
 	defer b.lock.Unlock()
 
-	return b.lruList.Len() + b.freeList.Len()
+	return b.used() + b.freeList.Len()
+}
+
+// Weight gets the total weight of entries used in the LRU
+func (b *LRUCache[K, T]) Weight() uint64 {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	return b.weight
+}
+
+// MaxWeight gets the weight limit set by WithMaxWeight, 0 if the LRU
+// is bounded by number of entries.
+func (b *LRUCache[K, T]) MaxWeight() uint64 {
+	return b.maxWeight
 }
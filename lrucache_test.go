@@ -10,7 +10,7 @@ import (
 
 func TestBasicExpiry(t *testing.T) {
 	t.Parallel()
-	b := NewLRUCache[string](3)
+	b := NewLRUCache[string, string](3)
 	if _, ok := b.Get("a"); ok {
 		t.Error("")
 	}
@@ -92,7 +92,7 @@ func TestBasicExpiry(t *testing.T) {
 
 func TestBasicNoExpiry(t *testing.T) {
 	t.Parallel()
-	b := NewLRUCache[string](3)
+	b := NewLRUCache[string, string](3)
 	if _, ok := b.Get("a"); ok {
 		t.Error("")
 	}
@@ -157,7 +157,7 @@ func TestBasicNoExpiry(t *testing.T) {
 
 func TestNil(t *testing.T) {
 	t.Parallel()
-	b := NewLRUCache[string](3)
+	b := NewLRUCache[string, string](3)
 
 	// value nil
 	if _, ok := b.Get("a"); ok != false {
@@ -189,7 +189,7 @@ func rec(foo func()) (recovered int) {
 
 func TestPanicByValue(t *testing.T) {
 	t.Parallel()
-	b := NewLRUCache[string](3)
+	b := NewLRUCache[string, string](3)
 
 	b.Set("a", "a", time.Time{})
 
@@ -213,7 +213,7 @@ func TestPanicByValue(t *testing.T) {
 
 func TestZeroLength(t *testing.T) {
 	t.Parallel()
-	b := NewLRUCache[string](0)
+	b := NewLRUCache[string, string](0)
 
 	if _, ok := b.Get("a"); ok {
 		t.Error("Expected miss")
@@ -229,7 +229,7 @@ func TestZeroLength(t *testing.T) {
 
 func TestExtra(t *testing.T) {
 	t.Parallel()
-	b := NewLRUCache[string](3)
+	b := NewLRUCache[string, string](3)
 	if _, ok := b.Get("a"); ok {
 		t.Error("")
 	}
@@ -269,8 +269,8 @@ func randomString(l int) string {
 	return string(bytes)
 }
 
-func createFilledBucket(expire time.Time) *LRUCache[string] {
-	b := NewLRUCache[string](1000)
+func createFilledBucket(expire time.Time) *LRUCache[string, string] {
+	b := NewLRUCache[string, string](1000)
 	for i := 0; i < 1000; i++ {
 		b.Set(randomString(2), "value", expire)
 	}
@@ -368,7 +368,7 @@ func BenchmarkConcurrentSetNXLRUCache(bb *testing.B) {
 	ch := make(chan bool)
 	worker := func() {
 		for i := 0; i < bb.N/cpu; i++ {
-			b.Set(randomString(2), "v", time.Time{})
+			b.SetIfAbsent(randomString(2), "v", time.Time{})
 		}
 		ch <- true
 	}
@@ -379,3 +379,196 @@ func BenchmarkConcurrentSetNXLRUCache(bb *testing.B) {
 		_ = <-ch
 	}
 }
+
+func TestNonStringKeys(t *testing.T) {
+	t.Parallel()
+	type pair struct{ a, b int }
+	b := NewLRUCache[pair, int](2)
+
+	b.Set(pair{1, 2}, 3, time.Time{})
+	b.Set(pair{2, 3}, 5, time.Time{})
+	if v, ok := b.Get(pair{1, 2}); !ok || v != 3 {
+		t.Error("expecting hit")
+	}
+
+	b.Set(pair{3, 4}, 7, time.Time{})
+	if _, ok := b.Get(pair{2, 3}); ok {
+		t.Error("expecting miss")
+	}
+
+	var c StringCache[int] = NewStringLRUCache[int](1)
+	c.Set("a", 1, time.Time{})
+	if v, _ := c.Get("a"); v != 1 {
+		t.Error("expecting hit")
+	}
+}
+
+func TestOnEvict(t *testing.T) {
+	t.Parallel()
+	b := NewLRUCache[string, string](2)
+
+	reasons := map[string]EvictReason{}
+	b.OnEvict = func(key string, value string, reason EvictReason) {
+		reasons[key] = reason
+		// Must not deadlock.
+		b.GetQuiet(key)
+	}
+
+	now := time.Now()
+	b.Set("a", "va", time.Time{})
+	b.Set("a", "va2", time.Time{})
+	if reasons["a"] != EvictReplaced {
+		t.Errorf("expecting %v, got %v", EvictReplaced, reasons["a"])
+	}
+
+	b.Set("b", "vb", now.Add(-time.Second))
+	b.Set("c", "vc", time.Time{})
+	if reasons["b"] != EvictExpired {
+		t.Errorf("expecting %v, got %v", EvictExpired, reasons["b"])
+	}
+
+	b.Set("d", "vd", time.Time{})
+	if reasons["a"] != EvictCapacity {
+		t.Errorf("expecting %v, got %v", EvictCapacity, reasons["a"])
+	}
+
+	b.Del("c")
+	if reasons["c"] != EvictDeleted {
+		t.Errorf("expecting %v, got %v", EvictDeleted, reasons["c"])
+	}
+
+	b.Clear()
+	if reasons["d"] != EvictCleared {
+		t.Errorf("expecting %v, got %v", EvictCleared, reasons["d"])
+	}
+	if len(reasons) != 4 {
+		t.Error("expecting different number of evictions")
+	}
+}
+
+func TestStats(t *testing.T) {
+	t.Parallel()
+	b := NewLRUCache[string, string](2)
+
+	now := time.Now()
+	b.Set("a", "va", now.Add(time.Second))
+	b.Set("a", "va", now.Add(-time.Second))
+	b.Set("b", "vb", now.Add(time.Second))
+	b.Get("a")
+	b.Get("miss")
+	b.GetStaleNow("a", now)
+	b.GetNotStaleNow("a", now)
+	b.Set("c", "vc", time.Time{})
+	b.Set("d", "vd", time.Time{})
+
+	s := b.Stats()
+	if s.Hits != 2 || s.Misses != 2 || s.StaleHits != 1 || s.Expirations != 1 {
+		t.Errorf("unexpected lookup stats %+v", s)
+	}
+	if s.Sets != 5 || s.Overwrites != 1 {
+		t.Errorf("unexpected set stats %+v", s)
+	}
+	if s.Evicted(EvictReplaced) != 1 || s.Evicted(EvictExpired) != 1 || s.Evicted(EvictCapacity) != 1 {
+		t.Errorf("unexpected eviction stats %+v", s)
+	}
+	if s.Len != 2 || s.Capacity != 2 || s.HitRatio() != 0.5 {
+		t.Errorf("unexpected stats %+v", s)
+	}
+}
+
+func TestWeighted(t *testing.T) {
+	t.Parallel()
+	b := NewLRUCache[string, string](1, WithMaxWeight(10))
+	b.Sizer = func(key string, value string) uint64 {
+		return uint64(len(value))
+	}
+
+	b.Set("a", "aaaa", time.Time{})
+	b.Set("b", "bbbb", time.Time{})
+	if b.Len() != 2 || b.Weight() != 8 {
+		t.Error("expecting different weight")
+	}
+
+	b.Set("c", "cccc", time.Time{})
+	if _, ok := b.Get("a"); ok {
+		t.Error("expecting miss")
+	}
+	if b.Len() != 2 || b.Weight() != 8 {
+		t.Error("expecting different weight")
+	}
+
+	b.SetWeighted("d", "d", 10, time.Time{})
+	if b.Len() != 1 || b.Weight() != 10 {
+		t.Error("expecting different weight")
+	}
+
+	// Too heavy to ever fit.
+	b.SetWeighted("e", "e", 11, time.Time{})
+	if _, ok := b.Get("e"); ok {
+		t.Error("expecting miss")
+	}
+	b.SetWeighted("d", "dd", 11, time.Time{})
+	if v, ok := b.Get("d"); !ok || v != "d" {
+		t.Error("expecting too heavy overwrite to keep the old value")
+	}
+	if b.MaxWeight() != 10 {
+		t.Error("expecting different max weight")
+	}
+}
+
+func TestWeightedZero(t *testing.T) {
+	t.Parallel()
+	b := NewLRUCache[int, int](1, WithMaxWeight(10))
+
+	for i := 0; i < 1000; i++ {
+		b.SetWeighted(i, i, 0, time.Time{})
+	}
+	if b.Len() != 10 || b.Weight() != 10 {
+		t.Errorf("expecting zero weights to count as 1, got %d entries", b.Len())
+	}
+	if b.Capacity() > 20 {
+		t.Errorf("expecting bounded allocation, got capacity %d", b.Capacity())
+	}
+}
+
+func TestExpireGracePeriod(t *testing.T) {
+	t.Parallel()
+	b := NewLRUCache[string, string](3)
+	b.ExpireGracePeriod = time.Minute
+
+	now := time.Now()
+	b.Set("a", "va", now)
+	if _, ok := b.GetNotStaleNow("a", now.Add(30*time.Second)); ok {
+		t.Error("expecting miss")
+	}
+	if _, ok := b.GetQuiet("a"); !ok {
+		t.Error("expecting entry to be kept within the grace period")
+	}
+	if _, ok := b.GetNotStaleNow("a", now.Add(2*time.Minute)); ok {
+		t.Error("expecting miss")
+	}
+	if _, ok := b.GetQuiet("a"); ok {
+		t.Error("expecting entry to be removed after the grace period")
+	}
+
+	b.ExpireGracePeriod = 0
+	b.Set("b", "vb", now)
+	b.GetNotStaleNow("b", now.Add(time.Second))
+	if b.Len() != 0 {
+		t.Error("expecting entry to be removed without a grace period")
+	}
+}
+
+func TestNeverExpiring(t *testing.T) {
+	t.Parallel()
+	b := NewLRUCache[string, string](3)
+
+	b.Set("a", "va", time.Time{})
+	later := time.Now().Add(24 * time.Hour)
+	if v, ok := b.GetNotStaleNow("a", later); !ok || v != "va" {
+		t.Error("expecting entry without expiry to be fresh")
+	}
+	if v, ok, expired := b.GetStaleNow("a", later); !ok || expired || v != "va" {
+		t.Error("expecting entry without expiry not to be stale")
+	}
+}
@@ -4,80 +4,160 @@ package lrucache
 
 import (
 	"hash/crc32"
+	"hash/maphash"
+	"sync"
+	"sync/atomic"
 	"time"
 )
 
+// Hasher maps a key to a bucket of the MultiLRUCache. It must be
+// deterministic for the lifetime of the cache.
+type Hasher[K comparable] func(key K) uint64
+
 // MultiLRUCache data structure. Never dereference it or copy it by
 // value. Always use it through a pointer.
-type MultiLRUCache[T any] struct {
+type MultiLRUCache[K comparable, T any] struct {
 	buckets uint
-	cache   []*LRUCache[T]
+	cache   []*LRUCache[K, T]
+	hasher  Hasher[K]
+	clock   Clock
+
+	janitorLock sync.Mutex       // guards janitor and refresher
+	janitor     *janitor         // see StartJanitor
+	refresher   *refresher[K, T] // see StartRefresher
+	gen         *atomic.Uint64   // shared by the buckets, see WithGenerations
 }
 
 // Using this constructor is almost always wrong. Use NewMultiLRUCache instead.
-func (m *MultiLRUCache[T]) init(buckets, bucketCapacity uint) {
+func (m *MultiLRUCache[K, T]) init(buckets, bucketCapacity uint, hasher Hasher[K], opts ...Option) {
 	m.buckets = buckets
-	m.cache = make([]*LRUCache[T], buckets)
+	m.hasher = hasher
+	o := newOptions(opts)
+	m.clock = o.clock
+	if o.generations {
+		m.gen = new(atomic.Uint64)
+		opts = append(opts[:len(opts):len(opts)], withGeneration(m.gen))
+	}
+	m.cache = make([]*LRUCache[K, T], buckets)
 	for i := uint(0); i < buckets; i++ {
-		m.cache[i] = NewLRUCache[T](bucketCapacity)
+		m.cache[i] = NewLRUCache[K, T](bucketCapacity, opts...)
 	}
 }
 
 // Set the stale expiry grace period for each cache in the multicache instance.
-func (m *MultiLRUCache[T]) SetExpireGracePeriod(p time.Duration) {
+func (m *MultiLRUCache[K, T]) SetExpireGracePeriod(p time.Duration) {
 	for _, c := range m.cache {
 		c.ExpireGracePeriod = p
 	}
 }
 
-func NewMultiLRUCache[T any](buckets, bucketCapacity uint) *MultiLRUCache[T] {
-	m := &MultiLRUCache[T]{}
-	m.init(buckets, bucketCapacity)
+// Set the eviction callback for each cache in the multicache instance.
+func (m *MultiLRUCache[K, T]) SetOnEvict(fn func(key K, value T, reason EvictReason)) {
+	for _, c := range m.cache {
+		c.OnEvict = fn
+	}
+}
+
+// Set the entry weight function for each cache in the multicache instance.
+func (m *MultiLRUCache[K, T]) SetSizer(fn func(key K, value T) uint64) {
+	for _, c := range m.cache {
+		c.Sizer = fn
+	}
+}
+
+// Create new sharded cache instance. String keys are spread across
+// buckets using crc32, any other key type using hash/maphash.
+func NewMultiLRUCache[K comparable, T any](buckets, bucketCapacity uint, opts ...Option) *MultiLRUCache[K, T] {
+	return NewMultiLRUCacheWithHasher[K, T](buckets, bucketCapacity, defaultHasher[K](), opts...)
+}
+
+// Create new sharded cache instance using a custom hasher to pick
+// the bucket of a key.
+func NewMultiLRUCacheWithHasher[K comparable, T any](buckets, bucketCapacity uint, hasher Hasher[K], opts ...Option) *MultiLRUCache[K, T] {
+	m := &MultiLRUCache[K, T]{}
+	m.init(buckets, bucketCapacity, hasher, opts...)
 	return m
 }
 
-func (m *MultiLRUCache[T]) bucketNo(key string) uint {
-	// Arbitrary choice. Any fast hash will do.
-	return uint(crc32.ChecksumIEEE([]byte(key))) % m.buckets
+// StringMultiLRUCache is a MultiLRUCache keyed by strings.
+type StringMultiLRUCache[T any] = MultiLRUCache[string, T]
+
+// Create new string keyed sharded cache instance.
+func NewStringMultiLRUCache[T any](buckets, bucketCapacity uint, opts ...Option) *StringMultiLRUCache[T] {
+	return NewMultiLRUCache[string, T](buckets, bucketCapacity, opts...)
+}
+
+func defaultHasher[K comparable]() Hasher[K] {
+	var k K
+	if _, ok := any(k).(string); ok {
+		return func(key K) uint64 {
+			// Arbitrary choice. Any fast hash will do.
+			return uint64(crc32.ChecksumIEEE([]byte(any(key).(string))))
+		}
+	}
+	seed := maphash.MakeSeed()
+	return func(key K) uint64 {
+		return maphash.Comparable(seed, key)
+	}
+}
+
+func (m *MultiLRUCache[K, T]) bucketNo(key K) uint {
+	return uint(m.hasher(key) % uint64(m.buckets))
 }
 
-func (m *MultiLRUCache[T]) Set(key string, value T, expire time.Time) {
+func (m *MultiLRUCache[K, T]) Set(key K, value T, expire time.Time) {
 	m.cache[m.bucketNo(key)].Set(key, value, expire)
 }
 
-func (m *MultiLRUCache[T]) SetNow(key string, value T, expire time.Time, now time.Time) {
+func (m *MultiLRUCache[K, T]) SetNow(key K, value T, expire time.Time, now time.Time) {
 	m.cache[m.bucketNo(key)].SetNow(key, value, expire, now)
 }
 
-func (m *MultiLRUCache[T]) Get(key string) (value T, ok bool) {
+func (m *MultiLRUCache[K, T]) SetWeighted(key K, value T, weight uint64, expire time.Time) {
+	m.cache[m.bucketNo(key)].SetWeighted(key, value, weight, expire)
+}
+
+func (m *MultiLRUCache[K, T]) SetWeightedNow(key K, value T, weight uint64, expire time.Time, now time.Time) {
+	m.cache[m.bucketNo(key)].SetWeightedNow(key, value, weight, expire, now)
+}
+
+func (m *MultiLRUCache[K, T]) Get(key K) (value T, ok bool) {
 	return m.cache[m.bucketNo(key)].Get(key)
 }
 
-func (m *MultiLRUCache[T]) GetQuiet(key string) (value T, ok bool) {
+func (m *MultiLRUCache[K, T]) GetQuiet(key K) (value T, ok bool) {
 	return m.cache[m.bucketNo(key)].Get(key)
 }
 
-func (m *MultiLRUCache[T]) GetNotStale(key string) (value T, ok bool) {
+func (m *MultiLRUCache[K, T]) GetNotStale(key K) (value T, ok bool) {
 	return m.cache[m.bucketNo(key)].GetNotStale(key)
 }
 
-func (m *MultiLRUCache[T]) GetNotStaleNow(key string, now time.Time) (value T, ok bool) {
+func (m *MultiLRUCache[K, T]) GetNotStaleNow(key K, now time.Time) (value T, ok bool) {
 	return m.cache[m.bucketNo(key)].GetNotStaleNow(key, now)
 }
 
-func (m *MultiLRUCache[T]) GetStale(key string) (value T, ok, expired bool) {
+func (m *MultiLRUCache[K, T]) GetStale(key K) (value T, ok, expired bool) {
 	return m.cache[m.bucketNo(key)].GetStale(key)
 }
 
-func (m *MultiLRUCache[T]) GetStaleNow(key string, now time.Time) (value T, ok, expired bool) {
+func (m *MultiLRUCache[K, T]) GetStaleNow(key K, now time.Time) (value T, ok, expired bool) {
 	return m.cache[m.bucketNo(key)].GetStaleNow(key, now)
 }
 
-func (m *MultiLRUCache[T]) Del(key string) (value T, ok bool) {
+func (m *MultiLRUCache[K, T]) Del(key K) (value T, ok bool) {
 	return m.cache[m.bucketNo(key)].Del(key)
 }
 
-func (m *MultiLRUCache[T]) Clear() int {
+// Clear evicts all items from every bucket. It's a single atomic
+// operation with WithGenerations.
+func (m *MultiLRUCache[K, T]) Clear() int {
+	if m.gen != nil {
+		n := m.Len()
+		m.gen.Add(1)
+		return n
+	}
+
 	var s int
 	for _, c := range m.cache {
 		s += c.Clear()
@@ -85,7 +165,7 @@ func (m *MultiLRUCache[T]) Clear() int {
 	return s
 }
 
-func (m *MultiLRUCache[T]) Len() int {
+func (m *MultiLRUCache[K, T]) Len() int {
 	var s int
 	for _, c := range m.cache {
 		s += c.Len()
@@ -93,7 +173,7 @@ func (m *MultiLRUCache[T]) Len() int {
 	return s
 }
 
-func (m *MultiLRUCache[T]) Capacity() int {
+func (m *MultiLRUCache[K, T]) Capacity() int {
 	var s int
 	for _, c := range m.cache {
 		s += c.Capacity()
@@ -101,7 +181,23 @@ func (m *MultiLRUCache[T]) Capacity() int {
 	return s
 }
 
-func (m *MultiLRUCache[T]) Expire() int {
+func (m *MultiLRUCache[K, T]) Weight() uint64 {
+	var s uint64
+	for _, c := range m.cache {
+		s += c.Weight()
+	}
+	return s
+}
+
+func (m *MultiLRUCache[K, T]) MaxWeight() uint64 {
+	var s uint64
+	for _, c := range m.cache {
+		s += c.MaxWeight()
+	}
+	return s
+}
+
+func (m *MultiLRUCache[K, T]) Expire() int {
 	var s int
 	for _, c := range m.cache {
 		s += c.Expire()
@@ -109,7 +205,7 @@ func (m *MultiLRUCache[T]) Expire() int {
 	return s
 }
 
-func (m *MultiLRUCache[T]) ExpireNow(now time.Time) int {
+func (m *MultiLRUCache[K, T]) ExpireNow(now time.Time) int {
 	var s int
 	for _, c := range m.cache {
 		s += c.ExpireNow(now)
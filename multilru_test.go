@@ -3,6 +3,7 @@
 package lrucache
 
 import (
+	"context"
 	"runtime"
 	"testing"
 	"time"
@@ -11,7 +12,7 @@ import (
 func TestMultiLRUBasic(t *testing.T) {
 	t.Parallel()
 
-	m := NewMultiLRUCache[string](2, 3)
+	m := NewMultiLRUCache[string, string](2, 3)
 
 	if m.Capacity() != 6 {
 		t.Error("expecting different capacity")
@@ -94,8 +95,8 @@ func TestMultiLRUBasic(t *testing.T) {
 	}
 }
 
-func filledMultiLRU(expire time.Time) *MultiLRUCache[string] {
-	b := NewMultiLRUCache[string](4, 250)
+func filledMultiLRU(expire time.Time) *MultiLRUCache[string, string] {
+	b := NewMultiLRUCache[string, string](4, 250)
 	for i := 0; i < 1000; i++ {
 		b.Set(randomString(2), "value", expire)
 	}
@@ -152,7 +153,7 @@ func BenchmarkConcurrentSetNXMultiLRU(bb *testing.B) {
 	ch := make(chan bool)
 	worker := func() {
 		for i := 0; i < bb.N/cpu; i++ {
-			b.Set(randomString(2), "v", time.Time{})
+			b.SetIfAbsent(randomString(2), "v", time.Time{})
 		}
 		ch <- true
 	}
@@ -163,3 +164,87 @@ func BenchmarkConcurrentSetNXMultiLRU(bb *testing.B) {
 		_ = <-ch
 	}
 }
+
+func TestMultiLRUHasher(t *testing.T) {
+	t.Parallel()
+
+	m := NewMultiLRUCache[int, int](4, 8)
+	for i := 0; i < 16; i++ {
+		m.Set(i, i*i, time.Time{})
+	}
+	for i := 0; i < 16; i++ {
+		if v, ok := m.GetQuiet(i); ok && v != i*i {
+			t.Error("expecting different value")
+		}
+	}
+
+	// Everything lands in a single bucket.
+	s := NewMultiLRUCacheWithHasher[int, int](4, 2, func(int) uint64 { return 1 })
+	for i := 0; i < 4; i++ {
+		s.Set(i, i, time.Time{})
+	}
+	if s.Len() != 2 {
+		t.Error("expecting different length")
+	}
+}
+
+func TestMultiLRUOnEvict(t *testing.T) {
+	t.Parallel()
+
+	m := NewMultiLRUCache[string, string](2, 3)
+	n := 0
+	m.SetOnEvict(func(key string, value string, reason EvictReason) {
+		if reason != EvictCleared {
+			t.Error("expecting different reason")
+		}
+		n++
+	})
+	m.Set("a", "va", time.Time{})
+	m.Set("b", "vb", time.Time{})
+	m.Clear()
+	if n != 2 {
+		t.Error("expecting different number of evictions")
+	}
+}
+
+func TestMultiLRUStats(t *testing.T) {
+	t.Parallel()
+
+	m := NewMultiLRUCache[string, string](2, 3)
+	m.Set("a", "va", time.Time{})
+	m.Set("b", "vb", time.Time{})
+	m.Get("a")
+	m.Get("c")
+
+	s := m.Stats()
+	if s.Hits != 1 || s.Misses != 1 || s.Sets != 2 || s.Len != 2 || s.Capacity != 6 {
+		t.Errorf("unexpected stats %+v", s)
+	}
+}
+
+func TestMultiLRUGetOrLoad(t *testing.T) {
+	t.Parallel()
+
+	m := NewMultiLRUCache[int, string](2, 3)
+	v, err := m.GetOrLoad(context.Background(), 1, func(ctx context.Context) (string, time.Time, error) {
+		return "v1", time.Time{}, nil
+	})
+	if v != "v1" || err != nil {
+		t.Error("expecting loaded value")
+	}
+	if v, _ := m.Get(1); v != "v1" {
+		t.Error("expecting hit")
+	}
+}
+
+func TestMultiLRUWeighted(t *testing.T) {
+	t.Parallel()
+
+	m := NewMultiLRUCache[int, string](2, 0, WithMaxWeight(5))
+	for i := 0; i < 10; i++ {
+		m.SetWeighted(i, "v", 2, time.Time{})
+	}
+	if m.MaxWeight() != 10 || m.Weight() > 8 || m.Len() > 4 {
+		t.Error("expecting different weight")
+	}
+}
@@ -0,0 +1,44 @@
+// Copyright (c) 2013 CloudFlare, Inc.
+
+package lrucache
+
+import (
+	"sync/atomic"
+)
+
+// Option configures a cache at construction time. Options passed to
+// NewMultiLRUCache apply to every bucket.
+type Option func(*options)
+
+type options struct {
+	maxWeight uint64
+	policy    Policy
+	clock     Clock
+	earlyBeta float64
+	ttlJitter float64
+
+	maxPinned int
+	metadata  bool
+
+	generations bool
+	gen         *atomic.Uint64 // shared by the buckets of a multicache
+}
+
+func newOptions(opts []Option) options {
+	o := options{clock: systemClock{}}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// WithMaxWeight bounds the cache by the total weight of its entries
+// instead of their number. The capacity passed to the constructor
+// only sizes the initial allocation, more entries are allocated as
+// long as their weight fits. See LRUCache.SetWeighted and
+// LRUCache.Sizer.
+func WithMaxWeight(maxWeight uint64) Option {
+	return func(o *options) {
+		o.maxWeight = maxWeight
+	}
+}
@@ -0,0 +1,121 @@
+// Copyright (c) 2013 CloudFlare, Inc.
+
+package lrucache
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	// ErrPinLimit is returned when pinning more entries than allowed
+	// by WithMaxPinned.
+	ErrPinLimit = errors.New("lrucache: too many pinned entries")
+	// ErrNotFound is returned when pinning a missing key.
+	ErrNotFound = errors.New("lrucache: key not found")
+	// ErrNoRoom is returned by SetPinned when the item couldn't be
+	// stored, because it's heavier than the cache or every entry is
+	// pinned.
+	ErrNoRoom = errors.New("lrucache: no room for entry")
+)
+
+// WithMaxPinned caps the number of pinned entries, see Pin. Without
+// it the whole capacity may be pinned, leaving no room for other
+// entries. Like the capacity, the cap applies to every bucket of a
+// MultiLRUCache on its own, so up to n times the number of buckets
+// entries may be pinned in total.
+func WithMaxPinned(n uint) Option {
+	return func(o *options) {
+		o.maxPinned = int(n)
+	}
+}
+
+// Pin exempts an entry from eviction for capacity. It's only removed
+// by Del, Clear or expiry, overwriting it with Set keeps the pin.
+// Returns ErrNotFound if the key is missing or stale and
+// ErrPinLimit if too many entries are pinned. O(1)
+func (b *LRUCache[K, T]) Pin(key K) error {
+	b.lock.Lock()
+	defer b.unlock()
+
+	e := b.lookup(key)
+	if e == nil || e.expired(b.clock.Now()) {
+		return ErrNotFound
+	}
+	return b.pin(e)
+}
+
+// Unpin makes a pinned entry evictable again, as the most recently
+// used one. Reports whether the key was pinned. O(1)
+func (b *LRUCache[K, T]) Unpin(key K) bool {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	e := b.lookup(key)
+	if e == nil || !e.pinned {
+		return false
+	}
+	b.pinned.Remove(&e.element)
+	e.pinned = false
+	b.pinnedWeight -= e.weight
+	b.policy.add(e)
+	return true
+}
+
+// SetPinned adds an item to the cache overwriting existing one if it
+// exists and pins it, see Pin. Nothing is stored on error.
+func (b *LRUCache[K, T]) SetPinned(key K, value T, expire time.Time) error {
+	weight := b.weigh(key, value)
+
+	b.lock.Lock()
+	defer b.unlock()
+
+	if e := b.lookup(key); (e == nil || !e.pinned) && b.pinLimited() {
+		return ErrPinLimit
+	}
+	now := b.clock.Now()
+	e := b.set(key, value, weight, b.jitter(expire, now), now)
+	if e == nil {
+		return ErrNoRoom
+	}
+	return b.pin(e)
+}
+
+// Is there no room for another pinned entry. Cleared entries don't
+// count. Must be called with the lock held.
+func (b *LRUCache[K, T]) pinLimited() bool {
+	b.sync()
+	return b.maxPinned > 0 && b.pinned.Len()-b.stalePins >= b.maxPinned
+}
+
+// Move an entry from the policy to the pinned list. Must be called
+// with the lock held.
+func (b *LRUCache[K, T]) pin(e *entry[K, T]) error {
+	if e.pinned {
+		return nil
+	}
+	if b.pinLimited() {
+		return ErrPinLimit
+	}
+	b.policy.remove(e, EvictDeleted)
+	b.pinned.PushElementFront(&e.element)
+	b.pinnedWeight += e.weight
+	e.pinned = true
+	return nil
+}
+
+// Pin exempts an entry from eviction, see LRUCache.Pin. ErrPinLimit
+// is returned when the bucket of the key is at the WithMaxPinned cap.
+func (m *MultiLRUCache[K, T]) Pin(key K) error {
+	return m.cache[m.bucketNo(key)].Pin(key)
+}
+
+// Unpin makes a pinned entry evictable again, see LRUCache.Unpin.
+func (m *MultiLRUCache[K, T]) Unpin(key K) bool {
+	return m.cache[m.bucketNo(key)].Unpin(key)
+}
+
+// SetPinned adds an item and pins it, see LRUCache.SetPinned.
+func (m *MultiLRUCache[K, T]) SetPinned(key K, value T, expire time.Time) error {
+	return m.cache[m.bucketNo(key)].SetPinned(key, value, expire)
+}
@@ -0,0 +1,135 @@
+package lrucache
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestPin(t *testing.T) {
+	t.Parallel()
+	b := NewLRUCache[string, string](3, WithMaxPinned(2))
+
+	b.Set("a", "va", time.Time{})
+	if err := b.Pin("a"); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.SetPinned("b", "vb", time.Time{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.SetPinned("c", "vc", time.Time{}); !errors.Is(err, ErrPinLimit) {
+		t.Errorf("expecting pin limit, got %v", err)
+	}
+	if err := b.Pin("x"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expecting not found, got %v", err)
+	}
+
+	for _, key := range []string{"c", "d", "e"} {
+		b.Set(key, "v"+key, time.Time{})
+	}
+	if _, ok := b.GetQuiet("a"); !ok {
+		t.Error("expecting pinned entry to be kept")
+	}
+	if _, ok := b.GetQuiet("d"); ok {
+		t.Error("expecting unpinned entry to be evicted")
+	}
+	if b.Len() != 3 || b.Capacity() != 3 {
+		t.Error("expecting pinned entries to be counted")
+	}
+
+	// Overwriting keeps the pin.
+	b.Set("a", "va2", time.Time{})
+	if !b.Unpin("a") || b.Unpin("a") {
+		t.Error("expecting a to be pinned once")
+	}
+	b.Set("f", "vf", time.Time{})
+	b.Set("g", "vg", time.Time{})
+	if _, ok := b.GetQuiet("a"); ok {
+		t.Error("expecting unpinned entry to be evicted")
+	}
+
+	if _, ok := b.Del("b"); !ok {
+		t.Error("expecting pinned entry to be deleted")
+	}
+	if err := b.Pin("g"); err != nil {
+		t.Error(err)
+	}
+	if n := b.Clear(); n != 2 || b.Len() != 0 {
+		t.Errorf("expecting all entries cleared, got %d", n)
+	}
+}
+
+func TestPinAllCapacity(t *testing.T) {
+	t.Parallel()
+	m := NewMultiLRUCache[int, int](1, 2)
+
+	m.SetPinned(1, 1, time.Time{})
+	m.SetPinned(2, 2, time.Time{})
+	if err := m.SetPinned(3, 3, time.Time{}); !errors.Is(err, ErrNoRoom) {
+		t.Errorf("expecting no room, got %v", err)
+	}
+	m.Set(3, 3, time.Time{})
+	if _, ok := m.Get(3); ok {
+		t.Error("expecting no room for unpinned entry")
+	}
+	if m.Resize(1) != 0 || m.Capacity() != 2 {
+		t.Error("expecting pinned entries to be kept on resize")
+	}
+}
+
+func TestPinWeighted(t *testing.T) {
+	t.Parallel()
+	b := NewLRUCache[string, string](4, WithMaxWeight(10))
+	b.Sizer = func(key string, value string) uint64 {
+		return uint64(len(value))
+	}
+
+	b.SetPinned("a", "aaaaaa", time.Time{})
+	b.Set("b", "bb", time.Time{})
+
+	// Nothing changes when the new value doesn't fit next to the
+	// pinned one.
+	if b.CompareAndSwap("b", "bb", "bbbbb", time.Time{}, nil) {
+		t.Error("expecting no swap")
+	}
+	if v, ok := b.Update("b", func(old string, ok bool) (string, time.Time, bool) {
+		return "bbbbb", time.Time{}, true
+	}); !ok || v != "bb" {
+		t.Errorf("expecting old value, got %q %v", v, ok)
+	}
+	if err := b.SetPinned("b", "bbbbb", time.Time{}); !errors.Is(err, ErrNoRoom) {
+		t.Errorf("expecting no room, got %v", err)
+	}
+	if v, ok := b.GetQuiet("b"); !ok || v != "bb" {
+		t.Error("expecting old value to be kept")
+	}
+
+	// The pinned entry itself may grow.
+	b.Set("a", "aaaaaaaaa", time.Time{})
+	if _, ok := b.GetQuiet("b"); ok || b.Weight() != 9 {
+		t.Error("expecting unpinned entry to make room")
+	}
+}
+
+func TestPinGenerations(t *testing.T) {
+	t.Parallel()
+	b := NewLRUCache[int, int](2, WithGenerations(), WithMaxPinned(2))
+
+	b.SetPinned(1, 1, time.Time{})
+	b.SetPinned(2, 2, time.Time{})
+	b.Clear()
+
+	if err := b.SetPinned(3, 3, time.Time{}); err != nil {
+		t.Errorf("expecting cleared pins not to count, got %v", err)
+	}
+	b.Set(4, 4, time.Time{})
+	if _, ok := b.Get(3); !ok {
+		t.Error("expecting hit")
+	}
+	if _, ok := b.Get(4); !ok {
+		t.Error("expecting cleared pinned entries to be evicted")
+	}
+	if b.Len() != 2 || b.Stats().Evicted(EvictCleared) != 2 {
+		t.Error("expecting cleared pinned entries to be reclaimed")
+	}
+}
@@ -0,0 +1,141 @@
+// Copyright (c) 2013 CloudFlare, Inc.
+
+package lrucache
+
+// Policy selects which entry is evicted when the cache is full.
+type Policy int
+
+const (
+	PolicyLRU     Policy = iota // evict the least recently used entry
+	PolicyFIFO                  // evict the oldest entry, reads don't matter
+	PolicyLFU                   // evict the least frequently used entry, LRU among equals
+	PolicySLRU                  // segmented LRU, entries read twice are protected from scans
+	PolicyTinyLFU               // W-TinyLFU, admits entries to the main SLRU by estimated frequency
+	PolicyARC                   // adaptive replacement cache, balances recency and frequency
+	PolicySIEVE                 // SIEVE, reads only mark entries as visited
+	PolicyS3FIFO                // S3-FIFO, small and main FIFO queues with a ghost queue
+)
+
+func (p Policy) String() string {
+	switch p {
+	case PolicyLRU:
+		return "lru"
+	case PolicyFIFO:
+		return "fifo"
+	case PolicyLFU:
+		return "lfu"
+	case PolicySLRU:
+		return "slru"
+	case PolicyTinyLFU:
+		return "tinylfu"
+	case PolicyARC:
+		return "arc"
+	case PolicySIEVE:
+		return "sieve"
+	case PolicyS3FIFO:
+		return "s3fifo"
+	}
+	return "unknown"
+}
+
+// WithPolicy selects the eviction policy. The default is PolicyLRU.
+func WithPolicy(p Policy) Option {
+	return func(o *options) {
+		o.policy = p
+	}
+}
+
+// evictionPolicy keeps used entries in eviction order. Every used
+// entry is tracked by the policy, free entries never are. Entries are
+// linked through their element, so policies don't allocate per entry.
+type evictionPolicy[K comparable, T any] interface {
+	add(e *entry[K, T])                        // e became used
+	touch(e *entry[K, T])                      // e was read
+	remove(e *entry[K, T], reason EvictReason) // e is about to become free
+	victim() *entry[K, T]                      // entry to evict next, nil when empty
+	len() int                                  // number of used entries
+	resize(capacity int)                       // number of entries changed
+	walk(fn func(e *entry[K, T]) bool)         // visit entries from the next victim on, until fn returns false
+}
+
+func newPolicy[K comparable, T any](p Policy, capacity int) evictionPolicy[K, T] {
+	switch p {
+	case PolicyLRU:
+		return newLRUPolicy[K, T]()
+	case PolicyFIFO:
+		return newFIFOPolicy[K, T]()
+	case PolicyLFU:
+		return newLFUPolicy[K, T]()
+	case PolicySLRU:
+		return newSLRUPolicy[K, T](capacity)
+	case PolicyTinyLFU:
+		return newTinyLFUPolicy[K, T](capacity)
+	case PolicyARC:
+		return newARCPolicy[K, T](capacity)
+	case PolicySIEVE:
+		return newSievePolicy[K, T]()
+	case PolicyS3FIFO:
+		return newS3FIFOPolicy[K, T](capacity)
+	}
+	panic("lrucache: unknown policy")
+}
+
+// Number of entries policies size their segments for. Caches bounded
+// by weight may hold more entries than capacity.
+func policyCapacity(capacity, used int) int {
+	return max(capacity, used)
+}
+
+// Entries ordered by decreasing recency of use.
+type lruPolicy[K comparable, T any] struct {
+	lruList list[K, T]
+}
+
+func newLRUPolicy[K comparable, T any]() *lruPolicy[K, T] {
+	p := &lruPolicy[K, T]{}
+	p.lruList.Init()
+	return p
+}
+
+func (p *lruPolicy[K, T]) add(e *entry[K, T]) {
+	p.lruList.PushElementFront(&e.element)
+}
+
+func (p *lruPolicy[K, T]) touch(e *entry[K, T]) {
+	p.lruList.MoveToFront(&e.element)
+}
+
+func (p *lruPolicy[K, T]) remove(e *entry[K, T], reason EvictReason) {
+	p.lruList.Remove(&e.element)
+}
+
+func (p *lruPolicy[K, T]) victim() *entry[K, T] {
+	if el := p.lruList.Back(); el != nil {
+		return el.Value
+	}
+	return nil
+}
+
+func (p *lruPolicy[K, T]) len() int {
+	return p.lruList.Len()
+}
+
+func (p *lruPolicy[K, T]) resize(capacity int) {}
+
+func (p *lruPolicy[K, T]) walk(fn func(e *entry[K, T]) bool) {
+	p.lruList.walkBackward(fn)
+}
+
+// Entries ordered by decreasing insertion time. Same as LRU, except
+// reads don't move entries around.
+type fifoPolicy[K comparable, T any] struct {
+	lruPolicy[K, T]
+}
+
+func newFIFOPolicy[K comparable, T any]() *fifoPolicy[K, T] {
+	p := &fifoPolicy[K, T]{}
+	p.lruList.Init()
+	return p
+}
+
+func (p *fifoPolicy[K, T]) touch(e *entry[K, T]) {}
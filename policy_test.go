@@ -0,0 +1,134 @@
+package lrucache
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPolicyFIFO(t *testing.T) {
+	t.Parallel()
+	b := NewLRUCache[string, string](2, WithPolicy(PolicyFIFO))
+
+	b.Set("a", "va", time.Time{})
+	b.Set("b", "vb", time.Time{})
+	b.Get("a")
+	b.Set("c", "vc", time.Time{})
+
+	if _, ok := b.GetQuiet("a"); ok {
+		t.Error("expecting oldest entry to be evicted")
+	}
+	if _, ok := b.GetQuiet("b"); !ok {
+		t.Error("expecting hit")
+	}
+}
+
+func TestPolicyLFU(t *testing.T) {
+	t.Parallel()
+	b := NewLRUCache[string, string](3, WithPolicy(PolicyLFU))
+
+	b.Set("a", "va", time.Time{})
+	b.Set("b", "vb", time.Time{})
+	b.Set("c", "vc", time.Time{})
+	b.Get("a")
+	b.Get("a")
+	b.Get("b")
+	b.Get("c")
+	b.Get("c")
+
+	b.Set("d", "vd", time.Time{})
+	if _, ok := b.GetQuiet("b"); ok {
+		t.Error("expecting least frequently used entry to be evicted")
+	}
+
+	// d has a single hit, it goes first.
+	b.Set("e", "ve", time.Time{})
+	if _, ok := b.GetQuiet("d"); ok {
+		t.Error("expecting least frequently used entry to be evicted")
+	}
+
+	b.Del("a")
+	b.Del("c")
+	if b.Len() != 1 {
+		t.Error("expecting different length")
+	}
+	if b.Clear() != 1 || b.Len() != 0 {
+		t.Error("expecting different length")
+	}
+}
+
+func TestPolicySLRU(t *testing.T) {
+	t.Parallel()
+	b := NewLRUCache[int, int](10, WithPolicy(PolicySLRU))
+
+	for i := 0; i < 5; i++ {
+		b.Set(i, i, time.Time{})
+		b.Get(i)
+	}
+
+	// A scan of keys never read again doesn't push out the hot ones.
+	for i := 100; i < 200; i++ {
+		b.Set(i, i, time.Time{})
+	}
+	for i := 0; i < 5; i++ {
+		if _, ok := b.GetQuiet(i); !ok {
+			t.Errorf("expecting %d to be protected", i)
+		}
+	}
+	if b.Len() != 10 {
+		t.Error("expecting different length")
+	}
+}
+
+func TestPolicyOverwrite(t *testing.T) {
+	t.Parallel()
+	for _, policy := range []Policy{PolicyLFU, PolicySLRU, PolicyARC, PolicyS3FIFO} {
+		b := NewLRUCache[int, int](10, WithPolicy(policy))
+
+		b.Set(0, 0, time.Time{})
+		b.Get(0)
+		b.Get(0)
+		// Overwriting counts as a read, the entry stays hot.
+		b.Set(0, 1, time.Time{})
+		for i := 100; i < 200; i++ {
+			b.Set(i, i, time.Time{})
+		}
+		if v, ok := b.GetQuiet(0); !ok || v != 1 {
+			t.Errorf("%s: expecting overwritten entry to survive a scan", policy)
+		}
+	}
+
+	// Overwriting keeps the hits.
+	b := NewLRUCache[string, string](2, WithPolicy(PolicyLFU))
+	b.Set("a", "va", time.Time{})
+	b.Get("a")
+	b.Get("a")
+	b.Set("b", "vb", time.Time{})
+	b.Get("b")
+	b.Set("a", "va2", time.Time{})
+	b.Set("c", "vc", time.Time{})
+	if _, ok := b.GetQuiet("a"); !ok {
+		t.Error("expecting overwritten entry to keep its hits")
+	}
+}
+
+func TestPolicyExpiry(t *testing.T) {
+	t.Parallel()
+	for _, p := range []Policy{PolicyLRU, PolicyFIFO, PolicyLFU, PolicySLRU} {
+		b := NewLRUCache[string, string](2, WithPolicy(p))
+
+		past := time.Now().Add(-time.Second)
+		b.Set("a", "va", time.Time{})
+		b.Get("a")
+		b.Set("b", "vb", past)
+		b.Get("b")
+		b.Get("b")
+		b.Set("c", "vc", time.Time{})
+
+		if _, ok := b.GetQuiet("b"); ok {
+			t.Errorf("%v: expecting expired entry to be evicted first", p)
+		}
+		if b.Expire() != 0 || b.Len() != 2 {
+			t.Errorf("%v: expecting different length", p)
+		}
+	}
+}
@@ -4,29 +4,29 @@
 
 package lrucache
 
-type priorityQueue[T any] []*entry[T]
+type priorityQueue[K comparable, T any] []*entry[K, T]
 
-func (pq priorityQueue[T]) Len() int {
+func (pq priorityQueue[K, T]) Len() int {
 	return len(pq)
 }
 
-func (pq priorityQueue[T]) Less(i, j int) bool {
+func (pq priorityQueue[K, T]) Less(i, j int) bool {
 	return pq[i].expire.Before(pq[j].expire)
 }
 
-func (pq priorityQueue[T]) Swap(i, j int) {
+func (pq priorityQueue[K, T]) Swap(i, j int) {
 	pq[i], pq[j] = pq[j], pq[i]
 	pq[i].index = i
 	pq[j].index = j
 }
 
-func (pq *priorityQueue[T]) Push(x *entry[T]) {
+func (pq *priorityQueue[K, T]) Push(x *entry[K, T]) {
 	n := len(*pq)
 	x.index = n
 	*pq = append(*pq, x)
 }
 
-func (pq *priorityQueue[T]) Pop() *entry[T] {
+func (pq *priorityQueue[K, T]) Pop() *entry[K, T] {
 	old := *pq
 	n := len(old)
 	item := old[n-1]
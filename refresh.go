@@ -0,0 +1,146 @@
+// Copyright (c) 2013 CloudFlare, Inc.
+
+package lrucache
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// Queued refreshes per worker. Reads of stale entries finding the
+// queue full don't schedule a refresh, a later read will.
+const refreshQueuePerWorker = 64
+
+// ErrStopped is returned to GetOrLoad callers waiting for a refresh
+// canceled by Stop.
+var ErrStopped = errors.New("lrucache: stopped")
+
+// RefreshFunc fetches a fresh value for a stale key together with its
+// new expiry time.
+type RefreshFunc[K comparable, T any] func(ctx context.Context, key K) (value T, expire time.Time, err error)
+
+type refreshJob[K comparable, T any] struct {
+	cache *LRUCache[K, T]
+	key   K
+	call  *loadCall[T]
+}
+
+// Pool of workers reloading stale entries, possibly shared by the
+// buckets of a multicache.
+type refresher[K comparable, T any] struct {
+	refresh RefreshFunc[K, T]
+	queue   chan refreshJob[K, T]
+	ctx     context.Context
+	cancel  context.CancelFunc
+	wg      sync.WaitGroup
+}
+
+func startRefresher[K comparable, T any](refresh RefreshFunc[K, T], workers int) *refresher[K, T] {
+	workers = max(workers, 1)
+	r := &refresher[K, T]{
+		refresh: refresh,
+		queue:   make(chan refreshJob[K, T], workers*refreshQueuePerWorker),
+	}
+	r.ctx, r.cancel = context.WithCancel(context.Background())
+	r.wg.Add(workers)
+	for i := 0; i < workers; i++ {
+		go r.work()
+	}
+	return r
+}
+
+func (r *refresher[K, T]) work() {
+	defer r.wg.Done()
+	for {
+		select {
+		case <-r.ctx.Done():
+			return
+		case job := <-r.queue:
+			job.cache.load(r.ctx, job.key, job.call, func(ctx context.Context) (T, time.Time, error) {
+				value, expire, err := r.refresh(ctx, job.key)
+				if err != nil {
+					job.cache.counters.refreshErrors.Add(1)
+				} else {
+					job.cache.counters.refreshes.Add(1)
+				}
+				return value, expire, err
+			})
+		}
+	}
+}
+
+// Stop the workers and wait for them to exit. Refreshes still queued
+// fail with ErrStopped. Nil is a no-op.
+func (r *refresher[K, T]) shutdown() {
+	if r == nil {
+		return
+	}
+	r.cancel()
+	r.wg.Wait()
+	for {
+		select {
+		case job := <-r.queue:
+			job.cache.finishLoad(job.key, job.call, *new(T), ErrStopped)
+		default:
+			return
+		}
+	}
+}
+
+// StartRefresher enables refresh-ahead. A GetStale read of an entry
+// expired within ExpireGracePeriod (or at any time if it's zero)
+// still returns the stale value right away, but also schedules a
+// reload through refresh on a pool of workers. There is at most one
+// load of a key at a time, GetOrLoad callers join a refresh in
+// progress. When a refresh fails the stale value stays. Restarts the
+// pool if it's already running. Call Stop to release it.
+func (b *LRUCache[K, T]) StartRefresher(refresh RefreshFunc[K, T], workers int) {
+	r := startRefresher(refresh, workers)
+
+	b.lock.Lock()
+	r, b.refresher = b.refresher, r
+	b.lock.Unlock()
+	r.shutdown()
+}
+
+// Schedule a refresh of a stale entry unless one is in progress. Must
+// be called with the lock held.
+func (b *LRUCache[K, T]) scheduleRefresh(e *entry[K, T], now time.Time) {
+	if b.refresher == nil {
+		return
+	}
+	if b.ExpireGracePeriod != 0 && now.Sub(e.expire) > b.ExpireGracePeriod {
+		return
+	}
+	if _, ok := b.loads[e.key]; ok {
+		return
+	}
+
+	job := refreshJob[K, T]{b, e.key, &loadCall[T]{done: make(chan struct{})}}
+	select {
+	case b.refresher.queue <- job:
+		if b.loads == nil {
+			b.loads = make(map[K]*loadCall[T])
+		}
+		b.loads[e.key] = job.call
+	default:
+	}
+}
+
+// StartRefresher enables refresh-ahead for all the buckets, using a
+// single pool of workers. See LRUCache.StartRefresher.
+func (m *MultiLRUCache[K, T]) StartRefresher(refresh RefreshFunc[K, T], workers int) {
+	r := startRefresher(refresh, workers)
+
+	m.janitorLock.Lock()
+	for _, c := range m.cache {
+		c.lock.Lock()
+		c.refresher = r
+		c.lock.Unlock()
+	}
+	r, m.refresher = m.refresher, r
+	m.janitorLock.Unlock()
+	r.shutdown()
+}
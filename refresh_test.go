@@ -0,0 +1,145 @@
+package lrucache
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"GolangLRU/lrucachetest"
+)
+
+func TestRefresher(t *testing.T) {
+	t.Parallel()
+	clock := lrucachetest.NewFakeClock(time.Date(2013, 1, 1, 0, 0, 0, 0, time.UTC))
+	b := NewLRUCache[string, string](10, WithClock(clock))
+	b.ExpireGracePeriod = time.Minute
+
+	var calls atomic.Int32
+	release := make(chan struct{})
+	b.StartRefresher(func(ctx context.Context, key string) (string, time.Time, error) {
+		calls.Add(1)
+		<-release
+		return "fresh", clock.Now().Add(time.Hour), nil
+	}, 4)
+	defer b.Stop()
+
+	b.Set("a", "stale", clock.Now().Add(time.Second))
+	clock.Advance(2 * time.Second)
+
+	for i := 0; i < 10; i++ {
+		v, ok, expired := b.GetStale("a")
+		if !ok || !expired || v != "stale" {
+			t.Fatalf("expecting stale value, got %q %v %v", v, ok, expired)
+		}
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		v, err := b.GetOrLoad(context.Background(), "a", func(context.Context) (string, time.Time, error) {
+			t.Error("expecting GetOrLoad to join the refresh")
+			return "", time.Time{}, nil
+		})
+		if err != nil || v != "fresh" {
+			t.Errorf("expecting refreshed value, got %q %v", v, err)
+		}
+	}()
+	close(release)
+	<-done
+
+	if calls.Load() != 1 {
+		t.Errorf("expecting a single refresh, got %d", calls.Load())
+	}
+	if v, ok := b.GetNotStale("a"); !ok || v != "fresh" {
+		t.Error("expecting refreshed value")
+	}
+	if b.Stats().Refreshes != 1 {
+		t.Error("expecting refresh to be counted")
+	}
+}
+
+func TestRefresherError(t *testing.T) {
+	t.Parallel()
+	clock := lrucachetest.NewFakeClock(time.Date(2013, 1, 1, 0, 0, 0, 0, time.UTC))
+	b := NewLRUCache[string, string](10, WithClock(clock))
+	b.ExpireGracePeriod = time.Minute
+
+	var calls atomic.Int32
+	b.StartRefresher(func(ctx context.Context, key string) (string, time.Time, error) {
+		calls.Add(1)
+		return "", time.Time{}, errors.New("down")
+	}, 1)
+	defer b.Stop()
+
+	// Loads in flight prevent scheduling, read until a refresh goes
+	// through.
+	refresh := func(n int32, timeout time.Duration) bool {
+		deadline := time.Now().Add(timeout)
+		for calls.Load() < n && time.Now().Before(deadline) {
+			if v, ok, expired := b.GetStale("a"); !ok || !expired || v != "stale" {
+				t.Error("expecting stale value to be kept")
+			}
+			time.Sleep(time.Millisecond)
+		}
+		return calls.Load() >= n
+	}
+
+	b.Set("a", "stale", clock.Now().Add(time.Second))
+	clock.Advance(2 * time.Second)
+	if !refresh(1, time.Second) || !refresh(2, time.Second) {
+		t.Fatal("expecting failed refreshes to be retried")
+	}
+
+	// Past the grace period there's no refresh.
+	clock.Advance(time.Hour)
+	deadline := time.Now().Add(time.Second)
+	for inFlight(b, "a") && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	n := calls.Load()
+	if refresh(n+1, 20*time.Millisecond) {
+		t.Error("expecting no refresh outside the grace period")
+	}
+	if b.Stats().RefreshErrors != uint64(n) {
+		t.Error("expecting refresh errors to be counted")
+	}
+}
+
+func inFlight[K comparable, T any](b *LRUCache[K, T], key K) bool {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	_, ok := b.loads[key]
+	return ok
+}
+
+func TestRefresherStop(t *testing.T) {
+	t.Parallel()
+	clock := lrucachetest.NewFakeClock(time.Date(2013, 1, 1, 0, 0, 0, 0, time.UTC))
+	m := NewMultiLRUCache[int, int](4, 10, WithClock(clock))
+
+	started := make(chan struct{})
+	m.StartRefresher(func(ctx context.Context, key int) (int, time.Time, error) {
+		close(started)
+		<-ctx.Done()
+		return 0, time.Time{}, ctx.Err()
+	}, 1)
+
+	m.Set(1, 1, clock.Now().Add(time.Second))
+	clock.Advance(2 * time.Second)
+	m.GetStale(1)
+	<-started
+	m.Stop()
+	m.Stop()
+
+	if v, ok, _ := m.GetStale(1); !ok || v != 1 {
+		t.Error("expecting stale value to be kept")
+	}
+	if _, err := m.GetOrLoad(context.Background(), 1, func(context.Context) (int, time.Time, error) {
+		return 2, clock.Now().Add(time.Hour), nil
+	}); err != nil {
+		t.Errorf("expecting load after Stop, got %v", err)
+	}
+}
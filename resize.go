@@ -0,0 +1,75 @@
+// Copyright (c) 2013 CloudFlare, Inc.
+
+package lrucache
+
+// Resize changes the number of entries of the cache. Growing
+// allocates a new block of free entries, shrinking releases free
+// entries first and then evicts expired and least used ones. With
+// WithGenerations released entries are kept and reused when growing
+// again. Returns
+// the number of evicted entries. O(n) in the size difference.
+func (b *LRUCache[K, T]) Resize(capacity uint) int {
+	b.lock.Lock()
+	defer b.unlock()
+
+	current := uint(b.used() + b.freeList.Len())
+	if capacity > current {
+		b.allocEntries(capacity - current)
+	}
+
+	evicted := 0
+	now := b.clock.Now()
+	for i := capacity; i < current; i++ {
+		if b.freeList.Len() == 0 {
+			// Pinned entries can't be evicted.
+			if !b.evictEntry(now, nil) {
+				break
+			}
+			evicted++
+		}
+		el := b.freeList.Front()
+		b.freeList.Remove(el)
+		if b.gen != nil {
+			b.spare.PushElementFront(el)
+		}
+	}
+	b.policy.resize(int(capacity))
+	return evicted
+}
+
+// ResizeWeight changes the weight limit of the cache, evicting
+// entries until their weight fits, see WithMaxWeight. Returns the
+// number of evicted entries. Zero makes the cache bounded by its
+// current number of entries instead.
+func (b *LRUCache[K, T]) ResizeWeight(maxWeight uint64) int {
+	b.lock.Lock()
+	defer b.unlock()
+
+	b.maxWeight = maxWeight
+	evicted := 0
+	now := b.clock.Now()
+	for maxWeight > 0 && b.weight > maxWeight && b.evictEntry(now, nil) {
+		evicted++
+	}
+	return evicted
+}
+
+// Resize changes the number of entries of every bucket, see
+// LRUCache.Resize.
+func (m *MultiLRUCache[K, T]) Resize(bucketCapacity uint) int {
+	var s int
+	for _, c := range m.cache {
+		s += c.Resize(bucketCapacity)
+	}
+	return s
+}
+
+// ResizeWeight changes the weight limit of every bucket, see
+// LRUCache.ResizeWeight.
+func (m *MultiLRUCache[K, T]) ResizeWeight(bucketMaxWeight uint64) int {
+	var s int
+	for _, c := range m.cache {
+		s += c.ResizeWeight(bucketMaxWeight)
+	}
+	return s
+}
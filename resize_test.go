@@ -0,0 +1,104 @@
+package lrucache
+
+import (
+	"testing"
+	"time"
+)
+
+func TestResize(t *testing.T) {
+	t.Parallel()
+	b := NewLRUCache[int, int](4)
+
+	for i := 0; i < 4; i++ {
+		b.Set(i, i, time.Time{})
+	}
+	if b.Resize(8) != 0 || b.Capacity() != 8 || b.Len() != 4 {
+		t.Error("expecting growth without eviction")
+	}
+	for i := 4; i < 8; i++ {
+		b.Set(i, i, time.Time{})
+	}
+	if b.Len() != 8 {
+		t.Error("expecting different length")
+	}
+
+	b.Get(0)
+	if n := b.Resize(2); n != 6 {
+		t.Errorf("expecting 6 evictions, got %d", n)
+	}
+	if b.Capacity() != 2 || b.Len() != 2 {
+		t.Error("expecting different capacity")
+	}
+	if _, ok := b.GetQuiet(0); !ok {
+		t.Error("expecting most recent entries to stay")
+	}
+	if _, ok := b.GetQuiet(7); !ok {
+		t.Error("expecting most recent entries to stay")
+	}
+
+	b.Del(7)
+	if b.Resize(1) != 0 || b.Len() != 1 {
+		t.Error("expecting free entries to be released first")
+	}
+	if b.Resize(0) != 1 || b.Capacity() != 0 {
+		t.Error("expecting empty cache")
+	}
+}
+
+func TestResizePolicies(t *testing.T) {
+	t.Parallel()
+	for _, p := range []Policy{PolicyLRU, PolicyFIFO, PolicyLFU, PolicySLRU, PolicyTinyLFU, PolicyARC, PolicySIEVE, PolicyS3FIFO} {
+		b := NewLRUCache[int, int](100, WithPolicy(p))
+		for i := 0; i < 300; i++ {
+			b.Set(i%150, i, time.Time{})
+			b.Get(i % 50)
+		}
+		if n := b.Resize(10); n != 90 || b.Len() != 10 {
+			t.Errorf("%v: expecting 90 evictions, got %d", p, n)
+		}
+		b.Resize(50)
+		for i := 0; i < 300; i++ {
+			b.Set(i, i, time.Time{})
+		}
+		if b.Len() != 50 || b.Capacity() != 50 {
+			t.Errorf("%v: expecting different length", p)
+		}
+	}
+}
+
+func TestResizeGenerations(t *testing.T) {
+	t.Parallel()
+	b := NewLRUCache[int, int](10, WithGenerations())
+
+	for i := 0; i < 50; i++ {
+		b.Set(i, i, time.Time{})
+		b.Resize(5)
+		b.Resize(10)
+	}
+	if len(b.blocks) != 1 || b.Capacity() != 10 {
+		t.Errorf("expecting released entries to be reused, got %d blocks", len(b.blocks))
+	}
+	b.Clear()
+	for i := 0; i < 10; i++ {
+		b.Set(i, i, time.Time{})
+	}
+	if b.Len() != 10 {
+		t.Error("expecting different length")
+	}
+}
+
+func TestResizeWeight(t *testing.T) {
+	t.Parallel()
+	hasher := func(key int) uint64 { return uint64(key) }
+	m := NewMultiLRUCacheWithHasher[int, int](2, 0, hasher, WithMaxWeight(10))
+
+	for i := 0; i < 20; i++ {
+		m.SetWeighted(i, i, 1, time.Time{})
+	}
+	if m.Len() != 20 {
+		t.Error("expecting different length")
+	}
+	if n := m.ResizeWeight(5); n != 10 || m.Weight() != 10 {
+		t.Errorf("expecting 10 evictions, got %d", n)
+	}
+}
@@ -0,0 +1,112 @@
+// Copyright (c) 2013 CloudFlare, Inc.
+
+package lrucache
+
+const (
+	s3fifoSmallPercent = 10 // share of the capacity given to the small queue
+	s3fifoMaxHits      = 3  // reads counted per entry
+)
+
+// S3FIFOCache is a cache using the S3-FIFO eviction policy. New
+// entries go to a small FIFO queue, most of them are read at most
+// once and leave quickly. Entries read while in the small queue, or
+// seen again shortly after leaving it, move to the main FIFO queue
+// where each read buys them another round. Reads only bump a counter,
+// entries are moved at eviction time. Otherwise it's an LRUCache and
+// supports the same API.
+type S3FIFOCache[K comparable, T any] struct {
+	LRUCache[K, T]
+}
+
+// Create new S3-FIFO cache instance. Allocate all the needed memory,
+// ghost entries included. O(capacity)
+func NewS3FIFOCache[K comparable, T any](capacity uint, opts ...Option) *S3FIFOCache[K, T] {
+	c := &S3FIFOCache[K, T]{}
+	c.init(capacity, append(opts[:len(opts):len(opts)], WithPolicy(PolicyS3FIFO))...)
+	return c
+}
+
+var _ Cache[string, any] = (*S3FIFOCache[string, any])(nil)
+
+// Read counts are kept in entry.hits. Ghosts remember keys evicted
+// from the small queue.
+type s3fifoPolicy[K comparable, T any] struct {
+	small    list[K, T]
+	main     list[K, T]
+	ghost    list[K, T]
+	ghosts   ghostTable[K, T]
+	capacity int
+}
+
+func newS3FIFOPolicy[K comparable, T any](capacity int) *s3fifoPolicy[K, T] {
+	p := &s3fifoPolicy[K, T]{capacity: capacity}
+	p.small.Init()
+	p.main.Init()
+	p.ghost.Init()
+	p.ghosts.init(capacity - capacity*s3fifoSmallPercent/100)
+	return p
+}
+
+func (p *s3fifoPolicy[K, T]) add(e *entry[K, T]) {
+	e.hits = 0
+	if g := p.ghosts.find(e.key); g != nil {
+		p.ghosts.forget(g)
+		p.main.PushElementFront(&e.element)
+		return
+	}
+	p.small.PushElementFront(&e.element)
+}
+
+func (p *s3fifoPolicy[K, T]) touch(e *entry[K, T]) {
+	e.hits = min(e.hits+1, s3fifoMaxHits)
+}
+
+func (p *s3fifoPolicy[K, T]) remove(e *entry[K, T], reason EvictReason) {
+	from := e.element.list
+	from.Remove(&e.element)
+
+	if reason == EvictCapacity && from == &p.small {
+		for !p.ghosts.remember(e.key, &p.ghost) && p.ghost.Len() > 0 {
+			p.ghosts.forget(p.ghost.Back().Value)
+		}
+	}
+}
+
+// Entries that were read get moved on instead of being evicted.
+func (p *s3fifoPolicy[K, T]) victim() *entry[K, T] {
+	small := max(policyCapacity(p.capacity, p.len())*s3fifoSmallPercent/100, 1)
+	for {
+		if el := p.small.Back(); el != nil && (p.small.Len() >= small || p.main.Len() == 0) {
+			if el.Value.hits == 0 {
+				return el.Value
+			}
+			el.Value.hits = 0
+			p.small.Remove(el)
+			p.main.PushElementFront(el)
+			continue
+		}
+
+		el := p.main.Back()
+		if el == nil {
+			return nil
+		}
+		if el.Value.hits == 0 {
+			return el.Value
+		}
+		el.Value.hits--
+		p.main.MoveToFront(el)
+	}
+}
+
+func (p *s3fifoPolicy[K, T]) len() int {
+	return p.small.Len() + p.main.Len()
+}
+
+func (p *s3fifoPolicy[K, T]) resize(capacity int) {
+	p.capacity = capacity
+	p.ghosts.resize(capacity-capacity*s3fifoSmallPercent/100, &p.ghost)
+}
+
+func (p *s3fifoPolicy[K, T]) walk(fn func(e *entry[K, T]) bool) {
+	_ = p.small.walkBackward(fn) && p.main.walkBackward(fn)
+}
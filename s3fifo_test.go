@@ -0,0 +1,63 @@
+package lrucache
+
+import (
+	"testing"
+	"time"
+)
+
+func TestS3FIFO(t *testing.T) {
+	t.Parallel()
+	c := NewS3FIFOCache[int, int](20)
+	p := c.policy.(*s3fifoPolicy[int, int])
+
+	for i := 0; i < 20; i++ {
+		c.Set(i, i, time.Time{})
+	}
+	for i := 0; i < 5; i++ {
+		c.Get(i)
+	}
+
+	// One hit wonders are evicted from the small queue and leave
+	// ghosts, entries read while there move to main.
+	for i := 100; i < 120; i++ {
+		c.Set(i, i, time.Time{})
+	}
+	for i := 0; i < 5; i++ {
+		if _, ok := c.GetQuiet(i); !ok {
+			t.Errorf("expecting %d to survive", i)
+		}
+	}
+	if p.ghost.Len() == 0 {
+		t.Error("expecting ghosts")
+	}
+
+	// A key seen again shortly goes straight to main.
+	c.Set(19, 19, time.Time{})
+	if el := c.table[19].element; el.list != &p.main {
+		t.Error("expecting ghost hit to land in main")
+	}
+	if c.Len() != 20 {
+		t.Error("expecting different length")
+	}
+}
+
+func TestS3FIFOScan(t *testing.T) {
+	t.Parallel()
+	var c Cache[int, int] = NewS3FIFOCache[int, int](100)
+
+	for round := 0; round < 3; round++ {
+		for i := 0; i < 50; i++ {
+			if _, ok := c.Get(i); !ok {
+				c.Set(i, i, time.Time{})
+			}
+		}
+	}
+	for i := 1000; i < 2000; i++ {
+		c.Set(i, i, time.Time{})
+	}
+	for i := 0; i < 50; i++ {
+		if _, ok := c.GetQuiet(i); !ok {
+			t.Errorf("expecting %d to survive a scan", i)
+		}
+	}
+}
@@ -0,0 +1,79 @@
+// Copyright (c) 2013 CloudFlare, Inc.
+
+package lrucache
+
+// SieveCache is a cache using the SIEVE eviction policy. Entries are
+// kept in insertion order and reads only mark them as visited, they
+// are never moved. A hand sweeps from the oldest entry towards the
+// newest, clearing marks, and evicts the first entry it finds
+// unmarked. Otherwise it's an LRUCache and supports the same API.
+type SieveCache[K comparable, T any] struct {
+	LRUCache[K, T]
+}
+
+// Create new SIEVE cache instance. Allocate all the needed memory.
+// O(capacity)
+func NewSieveCache[K comparable, T any](capacity uint, opts ...Option) *SieveCache[K, T] {
+	c := &SieveCache[K, T]{}
+	c.init(capacity, append(opts[:len(opts):len(opts)], WithPolicy(PolicySIEVE))...)
+	return c
+}
+
+var _ Cache[string, any] = (*SieveCache[string, any])(nil)
+
+// The visited mark is kept in entry.hits. Touching an entry only sets
+// it, so unlike LRU a read doesn't modify the list.
+type sievePolicy[K comparable, T any] struct {
+	queue list[K, T]     // newest entries in front
+	hand  *element[K, T] // next entry to look at, nil to start over from the back
+}
+
+func newSievePolicy[K comparable, T any]() *sievePolicy[K, T] {
+	p := &sievePolicy[K, T]{}
+	p.queue.Init()
+	return p
+}
+
+func (p *sievePolicy[K, T]) add(e *entry[K, T]) {
+	e.hits = 0
+	p.queue.PushElementFront(&e.element)
+}
+
+func (p *sievePolicy[K, T]) touch(e *entry[K, T]) {
+	e.hits = 1
+}
+
+func (p *sievePolicy[K, T]) remove(e *entry[K, T], reason EvictReason) {
+	if p.hand == &e.element {
+		p.hand = e.element.Prev()
+	}
+	p.queue.Remove(&e.element)
+}
+
+func (p *sievePolicy[K, T]) victim() *entry[K, T] {
+	if p.queue.Len() == 0 {
+		return nil
+	}
+	el := p.hand
+	for {
+		if el == nil {
+			el = p.queue.Back()
+		}
+		if el.Value.hits == 0 {
+			p.hand = el
+			return el.Value
+		}
+		el.Value.hits = 0
+		el = el.Prev()
+	}
+}
+
+func (p *sievePolicy[K, T]) len() int {
+	return p.queue.Len()
+}
+
+func (p *sievePolicy[K, T]) resize(capacity int) {}
+
+func (p *sievePolicy[K, T]) walk(fn func(e *entry[K, T]) bool) {
+	p.queue.walkBackward(fn)
+}
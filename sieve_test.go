@@ -0,0 +1,47 @@
+package lrucache
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSieve(t *testing.T) {
+	t.Parallel()
+	c := NewSieveCache[string, string](3)
+
+	c.Set("a", "va", time.Time{})
+	c.Set("b", "vb", time.Time{})
+	c.Set("c", "vc", time.Time{})
+	c.Get("a")
+
+	// a is visited, the hand passes it and evicts b.
+	c.Set("d", "vd", time.Time{})
+	if _, ok := c.GetQuiet("b"); ok {
+		t.Error("expecting b to be evicted")
+	}
+	if _, ok := c.GetQuiet("a"); !ok {
+		t.Error("expecting a to survive")
+	}
+
+	// The hand continues from where it stopped.
+	c.Set("e", "ve", time.Time{})
+	if _, ok := c.GetQuiet("c"); ok {
+		t.Error("expecting c to be evicted")
+	}
+
+	// Everything visited, the hand wraps around.
+	c.Get("a")
+	c.Get("d")
+	c.Get("e")
+	c.Set("f", "vf", time.Time{})
+	if c.Len() != 3 {
+		t.Error("expecting different length")
+	}
+	if _, ok := c.GetQuiet("f"); !ok {
+		t.Error("expecting f to be stored")
+	}
+
+	if c.Clear() != 3 || c.Len() != 0 {
+		t.Error("expecting different length")
+	}
+}
@@ -0,0 +1,102 @@
+// Copyright (c) 2013 CloudFlare, Inc.
+
+package lrucache
+
+import (
+	"math/bits"
+)
+
+const (
+	sketchDepth      = 4  // rows of the count-min sketch
+	sketchMaxCount   = 15 // counters saturate like 4 bit ones
+	sketchResetRatio = 10 // additions per counter width before aging
+)
+
+// Count-min sketch estimating how often keys were seen, guarded by a
+// doorkeeper bloom filter so keys seen only once don't pollute the
+// counters. Once in a while all counts are halved so that the
+// estimate follows changes in popularity.
+type frequencySketch struct {
+	counters  []uint8 // sketchDepth rows of mask+1 counters
+	mask      uint64
+	door      []uint64 // doorkeeper bitset of mask+1 bits
+	additions int
+	resetAt   int
+}
+
+func newFrequencySketch(capacity int) *frequencySketch {
+	width := uint64(1) << bits.Len64(uint64(max(capacity, 16)-1))
+	return &frequencySketch{
+		counters: make([]uint8, sketchDepth*width),
+		mask:     width - 1,
+		door:     make([]uint64, (width+63)/64),
+		resetAt:  sketchResetRatio * int(width),
+	}
+}
+
+// Position of a key hash in row i.
+func (s *frequencySketch) index(h uint64, i int) uint64 {
+	h1, h2 := h, h>>32|1
+	return uint64(i)*(s.mask+1) + (h1+uint64(i)*h2)&s.mask
+}
+
+// Check and set the doorkeeper bits of a hash. Returns true if they
+// were all set already.
+func (s *frequencySketch) admit(h uint64) bool {
+	seen := true
+	for _, bit := range [2]uint64{h & s.mask, (h >> 32) & s.mask} {
+		word, mask := bit/64, uint64(1)<<(bit%64)
+		if s.door[word]&mask == 0 {
+			seen = false
+			s.door[word] |= mask
+		}
+	}
+	return seen
+}
+
+func (s *frequencySketch) inDoor(h uint64) bool {
+	for _, bit := range [2]uint64{h & s.mask, (h >> 32) & s.mask} {
+		if s.door[bit/64]&(uint64(1)<<(bit%64)) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Record an occurrence of a key hash.
+func (s *frequencySketch) increment(h uint64) {
+	if !s.admit(h) {
+		return
+	}
+	for i := 0; i < sketchDepth; i++ {
+		if c := &s.counters[s.index(h, i)]; *c < sketchMaxCount {
+			*c++
+		}
+	}
+	s.additions++
+	if s.additions >= s.resetAt {
+		s.reset()
+	}
+}
+
+// Estimated number of occurrences of a key hash.
+func (s *frequencySketch) estimate(h uint64) int {
+	m := uint8(sketchMaxCount)
+	for i := 0; i < sketchDepth; i++ {
+		m = min(m, s.counters[s.index(h, i)])
+	}
+	n := int(m)
+	if s.inDoor(h) {
+		n++
+	}
+	return n
+}
+
+// Age the sketch: halve all the counters and forget the doorkeeper.
+func (s *frequencySketch) reset() {
+	for i := range s.counters {
+		s.counters[i] /= 2
+	}
+	clear(s.door)
+	s.additions /= 2
+}
@@ -0,0 +1,69 @@
+// Copyright (c) 2013 CloudFlare, Inc.
+
+package lrucache
+
+// Share of used entries the protected segment of SLRU may hold.
+const slruProtectedPercent = 80
+
+// Segmented LRU. New entries start in the probation segment and move
+// to the protected one when read again, so a scan of keys read once
+// only pushes out other probation entries. Entries falling off the
+// protected segment get another chance in probation.
+type slruPolicy[K comparable, T any] struct {
+	probation list[K, T]
+	protected list[K, T]
+	capacity  int
+}
+
+func newSLRUPolicy[K comparable, T any](capacity int) *slruPolicy[K, T] {
+	p := &slruPolicy[K, T]{capacity: capacity}
+	p.probation.Init()
+	p.protected.Init()
+	return p
+}
+
+func (p *slruPolicy[K, T]) add(e *entry[K, T]) {
+	p.probation.PushElementFront(&e.element)
+}
+
+func (p *slruPolicy[K, T]) touch(e *entry[K, T]) {
+	if e.element.list == &p.protected {
+		p.protected.MoveToFront(&e.element)
+		return
+	}
+
+	p.probation.Remove(&e.element)
+	p.protected.PushElementFront(&e.element)
+	if p.protected.Len()*100 > policyCapacity(p.capacity, p.len())*slruProtectedPercent {
+		p.probation.PushElementFront(p.protected.PopElementBack())
+	}
+}
+
+func (p *slruPolicy[K, T]) remove(e *entry[K, T], reason EvictReason) {
+	e.element.list.Remove(&e.element)
+}
+
+func (p *slruPolicy[K, T]) victim() *entry[K, T] {
+	if el := p.probation.Back(); el != nil {
+		return el.Value
+	}
+	if el := p.protected.Back(); el != nil {
+		return el.Value
+	}
+	return nil
+}
+
+func (p *slruPolicy[K, T]) len() int {
+	return p.probation.Len() + p.protected.Len()
+}
+
+func (p *slruPolicy[K, T]) resize(capacity int) {
+	p.capacity = capacity
+	for p.protected.Len()*100 > policyCapacity(p.capacity, p.len())*slruProtectedPercent {
+		p.probation.PushElementFront(p.protected.PopElementBack())
+	}
+}
+
+func (p *slruPolicy[K, T]) walk(fn func(e *entry[K, T]) bool) {
+	_ = p.probation.walkBackward(fn) && p.protected.walkBackward(fn)
+}
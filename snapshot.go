@@ -0,0 +1,319 @@
+// Copyright (c) 2013 CloudFlare, Inc.
+
+package lrucache
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"hash/crc32"
+	"io"
+	"math"
+	"time"
+)
+
+// Snapshot format:
+//
+//	magic "LRUC", version byte
+//	uvarint number of entries
+//	for every entry, least valuable first:
+//	    uvarint key length, key
+//	    uvarint value length, value
+//	    varint expiry in unix nanoseconds, 0 if none
+//	    uvarint idle time in nanoseconds, 0 unless sliding
+//	    varint deadline in unix nanoseconds, 0 if none
+//	    uvarint weight
+//	    flags byte, 1 if pinned
+//	    uvarint number of tags, for every tag uvarint length, tag
+//	crc32 (IEEE) of all the above, big endian
+const (
+	snapshotMagic   = "LRUC"
+	snapshotVersion = 1
+
+	snapshotPinned = 1 << 0
+)
+
+var (
+	ErrSnapshotFormat   = errors.New("lrucache: invalid snapshot")
+	ErrSnapshotVersion  = errors.New("lrucache: unsupported snapshot version")
+	ErrSnapshotChecksum = errors.New("lrucache: snapshot checksum mismatch")
+)
+
+// Codec serializes keys and values for Snapshot and Restore.
+type Codec[K comparable, T any] interface {
+	EncodeKey(key K) ([]byte, error)
+	DecodeKey(data []byte) (K, error)
+	EncodeValue(value T) ([]byte, error)
+	DecodeValue(data []byte) (T, error)
+}
+
+// JSONCodec is a Codec using encoding/json.
+type JSONCodec[K comparable, T any] struct{}
+
+func (JSONCodec[K, T]) EncodeKey(key K) ([]byte, error) {
+	return json.Marshal(key)
+}
+
+func (JSONCodec[K, T]) DecodeKey(data []byte) (key K, err error) {
+	err = json.Unmarshal(data, &key)
+	return key, err
+}
+
+func (JSONCodec[K, T]) EncodeValue(value T) ([]byte, error) {
+	return json.Marshal(value)
+}
+
+func (JSONCodec[K, T]) DecodeValue(data []byte) (value T, err error) {
+	err = json.Unmarshal(data, &value)
+	return value, err
+}
+
+// A copy of an entry taken under the lock.
+type record[K comparable, T any] struct {
+	key      K
+	value    T
+	expire   time.Time
+	idle     time.Duration // see SetSliding
+	deadline time.Time
+	weight   uint64
+	pinned   bool
+	tags     []string
+}
+
+// Append copies of all the used entries, least valuable first.
+func (b *LRUCache[K, T]) records(dst []record[K, T]) []record[K, T] {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	b.sync()
+	add := func(e *entry[K, T]) bool {
+		if b.stale(e) {
+			return true
+		}
+		rec := record[K, T]{key: e.key, value: e.value, expire: e.expire, weight: e.weight, pinned: e.pinned}
+		if x := e.ext; x != nil {
+			rec.idle, rec.deadline, rec.tags = x.idle, x.deadline, x.tags
+		}
+		dst = append(dst, rec)
+		return true
+	}
+	b.policy.walk(add)
+	b.pinned.walkBackward(add)
+	return dst
+}
+
+// Snapshot writes all the entries with their expiry, weight, pin and
+// tags to w, sliding expiry included, in the order of eviction. The lock is only held while
+// copying the entries, encoding happens after it's released.
+func (b *LRUCache[K, T]) Snapshot(w io.Writer, codec Codec[K, T]) error {
+	return writeSnapshot(w, codec, b.records(nil))
+}
+
+// Restore reads entries written by Snapshot and stores them, in the
+// same order so that the least valuable ones are evicted first again.
+// Entries expired by now are skipped. Nothing is stored unless the
+// whole snapshot is valid. Entries are pinned again as long as
+// WithMaxPinned allows. Returns the number of stored entries.
+func (b *LRUCache[K, T]) Restore(r io.Reader, codec Codec[K, T]) (int, error) {
+	return readSnapshot(r, codec, b.clock.Now(), b.restore)
+}
+
+// Store an entry read from a snapshot. Reports whether it was stored.
+func (b *LRUCache[K, T]) restore(rec *record[K, T], now time.Time) bool {
+	b.lock.Lock()
+	defer b.unlock()
+
+	// The expiry was jittered when the entry was first stored.
+	e := b.set(rec.key, rec.value, rec.weight, rec.expire, now)
+	if e == nil {
+		return false
+	}
+	if rec.idle != 0 {
+		x := e.extend()
+		x.idle, x.deadline = rec.idle, rec.deadline
+	}
+	b.tag(e, rec.tags)
+	if rec.pinned {
+		b.pin(e)
+	}
+	return true
+}
+
+// Snapshot writes entries of all the buckets to w, see
+// LRUCache.Snapshot.
+func (m *MultiLRUCache[K, T]) Snapshot(w io.Writer, codec Codec[K, T]) error {
+	var records []record[K, T]
+	for _, c := range m.cache {
+		records = c.records(records)
+	}
+	return writeSnapshot(w, codec, records)
+}
+
+// Restore reads entries written by Snapshot, see LRUCache.Restore.
+// The snapshot may come from a cache with different number of
+// buckets.
+func (m *MultiLRUCache[K, T]) Restore(r io.Reader, codec Codec[K, T]) (int, error) {
+	return readSnapshot(r, codec, m.clock.Now(), func(rec *record[K, T], now time.Time) bool {
+		return m.cache[m.bucketNo(rec.key)].restore(rec, now)
+	})
+}
+
+func writeSnapshot[K comparable, T any](w io.Writer, codec Codec[K, T], records []record[K, T]) error {
+	crc := crc32.NewIEEE()
+	bw := bufio.NewWriter(io.MultiWriter(w, crc))
+
+	buf := append([]byte(snapshotMagic), snapshotVersion)
+	buf = binary.AppendUvarint(buf, uint64(len(records)))
+	for i := range records {
+		r := &records[i]
+		key, err := codec.EncodeKey(r.key)
+		if err != nil {
+			return err
+		}
+		value, err := codec.EncodeValue(r.value)
+		if err != nil {
+			return err
+		}
+		buf = binary.AppendUvarint(buf, uint64(len(key)))
+		buf = append(buf, key...)
+		buf = binary.AppendUvarint(buf, uint64(len(value)))
+		buf = append(buf, value...)
+
+		buf = binary.AppendVarint(buf, unixNano(r.expire))
+		buf = binary.AppendUvarint(buf, uint64(r.idle))
+		buf = binary.AppendVarint(buf, unixNano(r.deadline))
+		buf = binary.AppendUvarint(buf, r.weight)
+
+		var flags byte
+		if r.pinned {
+			flags |= snapshotPinned
+		}
+		buf = append(buf, flags)
+		buf = appendTags(buf, r.tags)
+
+		if _, err := bw.Write(buf); err != nil {
+			return err
+		}
+		buf = buf[:0]
+	}
+
+	if err := bw.Flush(); err != nil {
+		return err
+	}
+	_, err := w.Write(binary.BigEndian.AppendUint32(nil, crc.Sum32()))
+	return err
+}
+
+func readSnapshot[K comparable, T any](r io.Reader, codec Codec[K, T], now time.Time,
+	restore func(rec *record[K, T], now time.Time) bool) (int, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return 0, err
+	}
+	if len(data) < len(snapshotMagic)+1+4 || string(data[:len(snapshotMagic)]) != snapshotMagic {
+		return 0, ErrSnapshotFormat
+	}
+	if data[len(snapshotMagic)] != snapshotVersion {
+		return 0, ErrSnapshotVersion
+	}
+	body, sum := data[:len(data)-4], data[len(data)-4:]
+	if crc32.ChecksumIEEE(body) != binary.BigEndian.Uint32(sum) {
+		return 0, ErrSnapshotChecksum
+	}
+
+	// Decode everything first, so a broken snapshot stores nothing.
+	rd := bytes.NewReader(body[len(snapshotMagic)+1:])
+	n, err := binary.ReadUvarint(rd)
+	if err != nil || n > uint64(rd.Len()) {
+		return 0, ErrSnapshotFormat
+	}
+	records := make([]record[K, T], 0, n)
+	for i := uint64(0); i < n; i++ {
+		var rec record[K, T]
+		key, err := readWithLength(rd)
+		if err != nil {
+			return 0, err
+		}
+		if rec.key, err = codec.DecodeKey(key); err != nil {
+			return 0, err
+		}
+		value, err := readWithLength(rd)
+		if err != nil {
+			return 0, err
+		}
+		if rec.value, err = codec.DecodeValue(value); err != nil {
+			return 0, err
+		}
+		if rec.expire, err = readTime(rd); err != nil {
+			return 0, err
+		}
+		idle, err := binary.ReadUvarint(rd)
+		if err != nil || idle > math.MaxInt64 {
+			return 0, ErrSnapshotFormat
+		}
+		rec.idle = time.Duration(idle)
+		if rec.deadline, err = readTime(rd); err != nil {
+			return 0, err
+		}
+		if rec.weight, err = binary.ReadUvarint(rd); err != nil {
+			return 0, ErrSnapshotFormat
+		}
+		flags, err := rd.ReadByte()
+		if err != nil {
+			return 0, ErrSnapshotFormat
+		}
+		rec.pinned = flags&snapshotPinned != 0
+		if rec.tags, err = readTags(rd); err != nil {
+			return 0, err
+		}
+		records = append(records, rec)
+	}
+	if rd.Len() != 0 {
+		return 0, ErrSnapshotFormat
+	}
+
+	stored := 0
+	for i := range records {
+		rec := &records[i]
+		if !rec.expire.IsZero() && rec.expire.Before(now) {
+			continue
+		}
+		if restore(rec, now) {
+			stored++
+		}
+	}
+	return stored, nil
+}
+
+// Unix time in nanoseconds, 0 for the zero time.
+func unixNano(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.UnixNano()
+}
+
+func readTime(rd *bytes.Reader) (time.Time, error) {
+	n, err := binary.ReadVarint(rd)
+	if err != nil {
+		return time.Time{}, ErrSnapshotFormat
+	}
+	if n == 0 {
+		return time.Time{}, nil
+	}
+	return time.Unix(0, n), nil
+}
+
+func readWithLength(rd *bytes.Reader) ([]byte, error) {
+	l, err := binary.ReadUvarint(rd)
+	if err != nil || l > uint64(rd.Len()) {
+		return nil, ErrSnapshotFormat
+	}
+	data := make([]byte, l)
+	if _, err := io.ReadFull(rd, data); err != nil {
+		return nil, ErrSnapshotFormat
+	}
+	return data, nil
+}
@@ -0,0 +1,185 @@
+package lrucache
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"GolangLRU/lrucachetest"
+)
+
+func TestSnapshot(t *testing.T) {
+	t.Parallel()
+	b := NewLRUCache[string, int](3)
+
+	now := time.Now()
+	b.Set("a", 1, time.Time{})
+	b.Set("b", 2, now.Add(time.Hour))
+	b.Set("c", 3, now.Add(-time.Hour))
+	b.Get("a")
+
+	var buf bytes.Buffer
+	if err := b.Snapshot(&buf, JSONCodec[string, int]{}); err != nil {
+		t.Fatal(err)
+	}
+
+	r := NewLRUCache[string, int](3)
+	n, err := r.Restore(bytes.NewReader(buf.Bytes()), JSONCodec[string, int]{})
+	if err != nil || n != 2 {
+		t.Fatalf("expecting 2 entries restored, got %d, %v", n, err)
+	}
+	if _, ok := r.GetQuiet("c"); ok {
+		t.Error("expecting expired entry to be dropped")
+	}
+
+	// Recency order is kept: b is evicted first.
+	r.Set("d", 4, time.Time{})
+	r.Set("e", 5, time.Time{})
+	if _, ok := r.GetQuiet("b"); ok {
+		t.Error("expecting b to be evicted")
+	}
+	if v, _ := r.GetQuiet("a"); v != 1 {
+		t.Error("expecting hit")
+	}
+
+	r = NewLRUCache[string, int](3)
+	r.Restore(bytes.NewReader(buf.Bytes()), JSONCodec[string, int]{})
+	if v, ok := r.GetNotStaleNow("b", now.Add(time.Minute)); v != 2 || !ok {
+		t.Error("expecting hit")
+	}
+	if _, ok := r.GetNotStaleNow("b", now.Add(2*time.Hour)); ok {
+		t.Error("expecting expiry to be restored")
+	}
+}
+
+func TestSnapshotJitter(t *testing.T) {
+	t.Parallel()
+	b := NewLRUCache[string, int](3, WithTTLJitter(0.5))
+
+	b.Set("a", 1, time.Now().Add(time.Hour))
+	expire, _ := b.ExpiresAt("a")
+
+	var buf bytes.Buffer
+	b.Snapshot(&buf, JSONCodec[string, int]{})
+	r := NewLRUCache[string, int](3, WithTTLJitter(0.5))
+	r.Restore(&buf, JSONCodec[string, int]{})
+	if got, _ := r.ExpiresAt("a"); !got.Equal(expire) {
+		t.Errorf("expecting expiry to be restored as is, got %v instead of %v", got, expire)
+	}
+}
+
+func TestRestoreTooHeavy(t *testing.T) {
+	t.Parallel()
+	b := NewLRUCache[string, string](3)
+	b.Set("a", "va", time.Time{})
+	b.SetWeighted("b", "vb", 5, time.Time{})
+
+	var buf bytes.Buffer
+	b.Snapshot(&buf, JSONCodec[string, string]{})
+	r := NewLRUCache[string, string](3, WithMaxWeight(4))
+	if n, err := r.Restore(&buf, JSONCodec[string, string]{}); err != nil || n != 1 {
+		t.Errorf("expecting only stored entries to be counted, got %d %v", n, err)
+	}
+}
+
+func TestSnapshotSliding(t *testing.T) {
+	t.Parallel()
+	clock := lrucachetest.NewFakeClock(time.Now())
+	b := NewLRUCache[string, int](3, WithClock(clock))
+	b.SetSliding("a", 1, time.Minute, 90*time.Second)
+
+	var buf bytes.Buffer
+	b.Snapshot(&buf, JSONCodec[string, int]{})
+	r := NewLRUCache[string, int](3, WithClock(clock))
+	r.Restore(&buf, JSONCodec[string, int]{})
+
+	clock.Advance(50 * time.Second)
+	r.Get("a")
+	clock.Advance(30 * time.Second)
+	if _, ok := r.GetNotStale("a"); !ok {
+		t.Error("expecting reads to push back the restored expiry")
+	}
+	clock.Advance(30 * time.Second)
+	if _, ok := r.GetNotStale("a"); ok {
+		t.Error("expecting the restored deadline to hold")
+	}
+}
+
+func TestSnapshotCorrupt(t *testing.T) {
+	t.Parallel()
+	b := NewLRUCache[string, string](3)
+	b.Set("a", "va", time.Time{})
+
+	var buf bytes.Buffer
+	if err := b.Snapshot(&buf, JSONCodec[string, string]{}); err != nil {
+		t.Fatal(err)
+	}
+	data := buf.Bytes()
+
+	r := NewLRUCache[string, string](3)
+	corrupt := bytes.Clone(data)
+	corrupt[len(corrupt)/2] ^= 0xff
+	if _, err := r.Restore(bytes.NewReader(corrupt), JSONCodec[string, string]{}); err != ErrSnapshotChecksum {
+		t.Errorf("expecting checksum error, got %v", err)
+	}
+
+	corrupt = bytes.Clone(data)
+	corrupt[4] = 99
+	if _, err := r.Restore(bytes.NewReader(corrupt), JSONCodec[string, string]{}); err != ErrSnapshotVersion {
+		t.Errorf("expecting version error, got %v", err)
+	}
+	if _, err := r.Restore(bytes.NewReader(data[:3]), JSONCodec[string, string]{}); err != ErrSnapshotFormat {
+		t.Errorf("expecting format error, got %v", err)
+	}
+	if r.Len() != 0 {
+		t.Error("expecting nothing restored")
+	}
+}
+
+func TestMultiLRUSnapshot(t *testing.T) {
+	t.Parallel()
+	m := NewMultiLRUCache[int, string](4, 10)
+	for i := 0; i < 20; i++ {
+		m.Set(i, "v", time.Time{})
+	}
+
+	var buf bytes.Buffer
+	if err := m.Snapshot(&buf, JSONCodec[int, string]{}); err != nil {
+		t.Fatal(err)
+	}
+
+	r := NewMultiLRUCache[int, string](2, 20)
+	if n, err := r.Restore(&buf, JSONCodec[int, string]{}); err != nil || n != m.Len() || r.Len() != n {
+		t.Errorf("expecting %d entries restored, got %d, %v", m.Len(), n, err)
+	}
+}
+
+func TestSnapshotPinsAndTags(t *testing.T) {
+	t.Parallel()
+	b := NewLRUCache[string, string](3)
+	b.SetPinned("a", "va", time.Time{})
+	b.SetWithTags("b", "vb", time.Time{}, "t1", "t2")
+
+	var buf bytes.Buffer
+	if err := b.Snapshot(&buf, JSONCodec[string, string]{}); err != nil {
+		t.Fatal(err)
+	}
+
+	m := NewMultiLRUCache[string, string](1, 2)
+	if n, err := m.Restore(&buf, JSONCodec[string, string]{}); err != nil || n != 2 {
+		t.Fatalf("expecting 2 entries restored, got %d %v", n, err)
+	}
+	m.Set("c", "vc", time.Time{})
+	m.Set("d", "vd", time.Time{})
+	if info, ok := m.Inspect("a"); !ok || !info.Pinned {
+		t.Error("expecting pin to be restored")
+	}
+
+	r := NewLRUCache[string, string](3)
+	buf.Reset()
+	b.Snapshot(&buf, JSONCodec[string, string]{})
+	r.Restore(&buf, JSONCodec[string, string]{})
+	if n := r.InvalidateTag("t2"); n != 1 {
+		t.Errorf("expecting tags to be restored, got %d", n)
+	}
+}
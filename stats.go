@@ -0,0 +1,111 @@
+// Copyright (c) 2013 CloudFlare, Inc.
+
+package lrucache
+
+import (
+	"sync/atomic"
+)
+
+// Stats is a point in time summary of the cache activity since its
+// creation.
+type Stats struct {
+	Hits        uint64 // lookups that found the key
+	Misses      uint64 // lookups that didn't find the key or found it expired
+	StaleHits   uint64 // GetStale lookups that returned an expired value
+	Expirations uint64 // GetNotStale lookups that found the key expired. Also counted as misses
+	Sets        uint64 // entries stored by Set
+	Overwrites  uint64 // entries stored by Set replacing an existing one
+
+	Refreshes     uint64 // stale entries reloaded in the background
+	RefreshErrors uint64 // failed background reloads
+
+	Evictions [evictReasons]uint64 // entries removed from the cache, indexed by EvictReason
+
+	Len      int // number of entries used
+	Capacity int // total number of entries
+}
+
+// Evicted returns the number of entries removed for a reason.
+func (s Stats) Evicted(reason EvictReason) uint64 {
+	return s.Evictions[reason]
+}
+
+// HitRatio returns hits divided by all lookups, zero when there
+// were none.
+func (s Stats) HitRatio() float64 {
+	total := s.Hits + s.Misses
+	if total == 0 {
+		return 0
+	}
+	return float64(s.Hits) / float64(total)
+}
+
+func (s *Stats) add(o Stats) {
+	s.Hits += o.Hits
+	s.Misses += o.Misses
+	s.StaleHits += o.StaleHits
+	s.Expirations += o.Expirations
+	s.Sets += o.Sets
+	s.Overwrites += o.Overwrites
+	s.Refreshes += o.Refreshes
+	s.RefreshErrors += o.RefreshErrors
+	for i := range s.Evictions {
+		s.Evictions[i] += o.Evictions[i]
+	}
+	s.Len += o.Len
+	s.Capacity += o.Capacity
+}
+
+// Counters are atomic so Stats doesn't need to take the lock, and
+// updating them doesn't make the critical section any longer.
+type counters struct {
+	hits        atomic.Uint64
+	misses      atomic.Uint64
+	staleHits   atomic.Uint64
+	expirations atomic.Uint64
+	sets        atomic.Uint64
+	overwrites  atomic.Uint64
+	evictions   [evictReasons]atomic.Uint64
+
+	refreshes     atomic.Uint64
+	refreshErrors atomic.Uint64
+}
+
+func (c *counters) load() Stats {
+	s := Stats{
+		Hits:        c.hits.Load(),
+		Misses:      c.misses.Load(),
+		StaleHits:   c.staleHits.Load(),
+		Expirations: c.expirations.Load(),
+		Sets:        c.sets.Load(),
+		Overwrites:  c.overwrites.Load(),
+
+		Refreshes:     c.refreshes.Load(),
+		RefreshErrors: c.refreshErrors.Load(),
+	}
+	for i := range s.Evictions {
+		s.Evictions[i] = c.evictions[i].Load()
+	}
+	return s
+}
+
+// Stats returns the cache counters along with its current length and
+// capacity.
+func (b *LRUCache[K, T]) Stats() Stats {
+	s := b.counters.load()
+
+	b.lock.Lock()
+	s.Len = b.len()
+	s.Capacity = b.used() + b.freeList.Len()
+	b.lock.Unlock()
+	return s
+}
+
+// Stats returns the counters summed over all the buckets.
+func (m *MultiLRUCache[K, T]) Stats() Stats {
+	var s Stats
+	for _, c := range m.cache {
+		s.add(c.Stats())
+	}
+	return s
+}
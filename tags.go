@@ -0,0 +1,120 @@
+// Copyright (c) 2013 CloudFlare, Inc.
+
+package lrucache
+
+import (
+	"bytes"
+	"encoding/binary"
+	"slices"
+	"time"
+)
+
+// SetWithTags adds an item to the cache overwriting existing one if
+// it exists, attaching tags to it. InvalidateTag removes all the
+// entries with a tag. Set replaces an entry with one without tags.
+func (b *LRUCache[K, T]) SetWithTags(key K, value T, expire time.Time, tags ...string) {
+	weight := b.weigh(key, value)
+
+	b.lock.Lock()
+	defer b.unlock()
+
+	now := b.clock.Now()
+	if e := b.set(key, value, weight, b.jitter(expire, now), now); e != nil {
+		b.tag(e, tags)
+	}
+}
+
+// Attach tags to an entry. Must be called with the lock held.
+func (b *LRUCache[K, T]) tag(e *entry[K, T], tags []string) {
+	if len(tags) == 0 {
+		return
+	}
+
+	x := e.extend()
+	x.tags = slices.Compact(slices.Sorted(slices.Values(tags)))
+	if b.tags == nil {
+		b.tags = make(map[string]map[*entry[K, T]]struct{})
+	}
+	for _, tag := range x.tags {
+		entries := b.tags[tag]
+		if entries == nil {
+			entries = make(map[*entry[K, T]]struct{})
+			b.tags[tag] = entries
+		}
+		entries[e] = struct{}{}
+	}
+}
+
+// Drop an entry from the tag index. Must be called with the lock held.
+func (b *LRUCache[K, T]) untag(e *entry[K, T]) {
+	if e.ext == nil {
+		return
+	}
+	for _, tag := range e.ext.tags {
+		entries := b.tags[tag]
+		delete(entries, e)
+		if len(entries) == 0 {
+			delete(b.tags, tag)
+		}
+	}
+	e.ext.tags = nil
+}
+
+// Append the tags of an entry to a snapshot, see Snapshot.
+func appendTags(buf []byte, tags []string) []byte {
+	buf = binary.AppendUvarint(buf, uint64(len(tags)))
+	for _, tag := range tags {
+		buf = binary.AppendUvarint(buf, uint64(len(tag)))
+		buf = append(buf, tag...)
+	}
+	return buf
+}
+
+// Read the tags of an entry from a snapshot, see Restore.
+func readTags(rd *bytes.Reader) ([]string, error) {
+	n, err := binary.ReadUvarint(rd)
+	if err != nil || n > uint64(rd.Len()) {
+		return nil, ErrSnapshotFormat
+	}
+	var tags []string
+	for i := uint64(0); i < n; i++ {
+		tag, err := readWithLength(rd)
+		if err != nil {
+			return nil, err
+		}
+		tags = append(tags, string(tag))
+	}
+	return tags, nil
+}
+
+// InvalidateTag removes every entry with a tag. Returns the number of
+// entries removed. O(number of entries with the tag)
+func (b *LRUCache[K, T]) InvalidateTag(tag string) int {
+	b.lock.Lock()
+	defer b.unlock()
+
+	b.sync()
+	n := 0
+	for e := range b.tags[tag] {
+		if !b.stale(e) {
+			n++
+		}
+		b.removeEntry(e, EvictDeleted)
+	}
+	return n
+}
+
+// SetWithTags adds an item with tags, see LRUCache.SetWithTags.
+func (m *MultiLRUCache[K, T]) SetWithTags(key K, value T, expire time.Time, tags ...string) {
+	m.cache[m.bucketNo(key)].SetWithTags(key, value, expire, tags...)
+}
+
+// InvalidateTag removes every entry with a tag from all the buckets,
+// see LRUCache.InvalidateTag.
+func (m *MultiLRUCache[K, T]) InvalidateTag(tag string) int {
+	n := 0
+	for _, c := range m.cache {
+		n += c.InvalidateTag(tag)
+	}
+	return n
+}
@@ -0,0 +1,53 @@
+package lrucache
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTags(t *testing.T) {
+	t.Parallel()
+	b := NewLRUCache[string, string](3)
+
+	b.SetWithTags("a", "va", time.Time{}, "row:1", "row:2")
+	b.SetWithTags("b", "vb", time.Time{}, "row:2", "row:2")
+	b.SetWithTags("c", "vc", time.Time{}, "row:3")
+
+	if n := b.InvalidateTag("row:2"); n != 2 {
+		t.Errorf("expecting 2 invalidations, got %d", n)
+	}
+	if _, ok := b.Get("a"); ok {
+		t.Error("expecting miss")
+	}
+	if n := b.InvalidateTag("row:1"); n != 0 {
+		t.Error("expecting index to be cleaned up on removal")
+	}
+
+	// Overwriting or evicting drops the tags.
+	b.Set("c", "vc", time.Time{})
+	if n := b.InvalidateTag("row:3"); n != 0 {
+		t.Error("expecting overwrite to drop tags")
+	}
+	b.SetWithTags("d", "vd", time.Time{}, "row:4")
+	b.Set("e", "ve", time.Time{})
+	b.Set("f", "vf", time.Time{})
+	b.Set("g", "vg", time.Time{})
+	if len(b.tags) != 0 {
+		t.Error("expecting eviction to clean up the index")
+	}
+}
+
+func TestMultiLRUTags(t *testing.T) {
+	t.Parallel()
+	m := NewMultiLRUCache[int, int](4, 20)
+
+	for i := 0; i < 20; i++ {
+		m.SetWithTags(i, i, time.Time{}, "all")
+	}
+	if n := m.InvalidateTag("all"); n != 20 {
+		t.Errorf("expecting 20 invalidations, got %d", n)
+	}
+	if m.Len() != 0 {
+		t.Error("expecting empty cache")
+	}
+}
@@ -0,0 +1,127 @@
+// Copyright (c) 2013 CloudFlare, Inc.
+
+package lrucache
+
+import (
+	"hash/maphash"
+)
+
+// Share of the capacity given to the admission window of W-TinyLFU.
+const tinyLFUWindowPercent = 1
+
+// W-TinyLFU. New entries land in a small LRU window. Entries falling
+// off the window compete with the victim of the main segmented LRU,
+// and only the one estimated to be used more often by the frequency
+// sketch stays. This keeps hit rates high for skewed key popularity
+// while still letting bursts of new keys in through the window.
+type tinyLFUPolicy[K comparable, T any] struct {
+	window    list[K, T]
+	probation list[K, T]
+	protected list[K, T]
+	capacity  int
+	sketch    *frequencySketch
+	seed      maphash.Seed
+}
+
+func newTinyLFUPolicy[K comparable, T any](capacity int) *tinyLFUPolicy[K, T] {
+	p := &tinyLFUPolicy[K, T]{
+		capacity: capacity,
+		sketch:   newFrequencySketch(capacity),
+		seed:     maphash.MakeSeed(),
+	}
+	p.window.Init()
+	p.probation.Init()
+	p.protected.Init()
+	return p
+}
+
+func (p *tinyLFUPolicy[K, T]) hash(e *entry[K, T]) uint64 {
+	return maphash.Comparable(p.seed, e.key)
+}
+
+// Sizes of the window and the main segments.
+func (p *tinyLFUPolicy[K, T]) limits() (window, main, protected int) {
+	c := policyCapacity(p.capacity, p.len())
+	window = max(c*tinyLFUWindowPercent/100, 1)
+	main = c - window
+	protected = main * slruProtectedPercent / 100
+	return
+}
+
+func (p *tinyLFUPolicy[K, T]) add(e *entry[K, T]) {
+	p.sketch.increment(p.hash(e))
+	p.window.PushElementFront(&e.element)
+
+	// While there is room, window overflow goes straight to main.
+	window, main, _ := p.limits()
+	if p.window.Len() > window && p.probation.Len()+p.protected.Len() < main {
+		p.probation.PushElementFront(p.window.PopElementBack())
+	}
+}
+
+func (p *tinyLFUPolicy[K, T]) touch(e *entry[K, T]) {
+	p.sketch.increment(p.hash(e))
+
+	switch e.element.list {
+	case &p.window:
+		p.window.MoveToFront(&e.element)
+	case &p.protected:
+		p.protected.MoveToFront(&e.element)
+	default:
+		p.probation.Remove(&e.element)
+		p.protected.PushElementFront(&e.element)
+		if _, _, protected := p.limits(); p.protected.Len() > protected {
+			p.probation.PushElementFront(p.protected.PopElementBack())
+		}
+	}
+}
+
+func (p *tinyLFUPolicy[K, T]) remove(e *entry[K, T], reason EvictReason) {
+	e.element.list.Remove(&e.element)
+}
+
+// Room is made before an entry is added, so once the window is full
+// its oldest entry is about to overflow into main. It duels the main
+// victim and the loser is evicted. May move the winner to main.
+func (p *tinyLFUPolicy[K, T]) victim() *entry[K, T] {
+	mainVictim := p.probation.Back()
+	if mainVictim == nil {
+		mainVictim = p.protected.Back()
+	}
+
+	candidate := p.window.Back()
+	if window, _, _ := p.limits(); p.window.Len() < window && mainVictim != nil {
+		return mainVictim.Value
+	}
+	if candidate == nil {
+		return nil
+	}
+	if mainVictim == nil {
+		return candidate.Value
+	}
+
+	if p.sketch.estimate(p.hash(candidate.Value)) > p.sketch.estimate(p.hash(mainVictim.Value)) {
+		p.window.Remove(candidate)
+		p.probation.PushElementFront(candidate)
+		return mainVictim.Value
+	}
+	return candidate.Value
+}
+
+func (p *tinyLFUPolicy[K, T]) len() int {
+	return p.window.Len() + p.probation.Len() + p.protected.Len()
+}
+
+// The sketch is sized for the capacity, so a new one is started.
+func (p *tinyLFUPolicy[K, T]) resize(capacity int) {
+	p.capacity = capacity
+	p.sketch = newFrequencySketch(capacity)
+	_, _, protected := p.limits()
+	for p.protected.Len() > protected {
+		p.probation.PushElementFront(p.protected.PopElementBack())
+	}
+}
+
+func (p *tinyLFUPolicy[K, T]) walk(fn func(e *entry[K, T]) bool) {
+	_ = p.window.walkBackward(fn) && p.probation.walkBackward(fn) && p.protected.walkBackward(fn)
+}
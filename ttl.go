@@ -0,0 +1,140 @@
+// Copyright (c) 2013 CloudFlare, Inc.
+
+package lrucache
+
+import (
+	"time"
+)
+
+// SetWithTTL adds an item to the cache overwriting existing one if it
+// exists. The item expires ttl from now, a zero ttl means it never
+// expires.
+func (b *LRUCache[K, T]) SetWithTTL(key K, value T, ttl time.Duration) {
+	var expire time.Time
+	now := b.clock.Now()
+	if ttl != 0 {
+		expire = now.Add(ttl)
+	}
+	b.SetNow(key, value, expire, now)
+}
+
+// SetSliding adds an item expiring after being idle for a while. Every
+// read through Get, GetNotStale or GetStale while it's fresh pushes
+// its expiry idle from the time of the read, O(log(n)). A non zero
+// maxLifetime caps the expiry at maxLifetime from now, however often
+// the item is read.
+func (b *LRUCache[K, T]) SetSliding(key K, value T, idle, maxLifetime time.Duration) {
+	now := b.clock.Now()
+	expire := now.Add(idle)
+	var deadline time.Time
+	if maxLifetime > 0 {
+		deadline = now.Add(maxLifetime)
+		if expire.After(deadline) {
+			expire = deadline
+		}
+	}
+	weight := b.weigh(key, value)
+
+	b.lock.Lock()
+	defer b.unlock()
+
+	if e := b.set(key, value, weight, expire, now); e != nil {
+		x := e.extend()
+		x.idle, x.deadline = idle, deadline
+	}
+}
+
+// ExpiresAt gets the expiry time of a key without updating its LRU
+// score. It's zero if the entry never expires. O(1)
+func (b *LRUCache[K, T]) ExpiresAt(key K) (expire time.Time, ok bool) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	e := b.lookup(key)
+	if e == nil {
+		return time.Time{}, false
+	}
+	return e.expire, true
+}
+
+// TTL gets the time left before a key expires without updating its
+// LRU score. It's zero if the entry never expires and negative if
+// it's stale. O(1)
+func (b *LRUCache[K, T]) TTL(key K) (ttl time.Duration, ok bool) {
+	expire, ok := b.ExpiresAt(key)
+	if !ok || expire.IsZero() {
+		return 0, ok
+	}
+	return expire.Sub(b.clock.Now()), true
+}
+
+// Touch changes the expiry of a key in place, keeping its value and
+// LRU score. A zero expire means it never expires. A sliding entry
+// stops sliding, see SetSliding. Reports whether the key was found.
+// O(log(n))
+func (b *LRUCache[K, T]) Touch(key K, expire time.Time) bool {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	e := b.lookup(key)
+	if e == nil {
+		return false
+	}
+	b.setExpire(e, expire)
+	return true
+}
+
+// Persist removes the expiry of a key, see Touch.
+func (b *LRUCache[K, T]) Persist(key K) bool {
+	return b.Touch(key, time.Time{})
+}
+
+// Move an entry within the priorityQueue to match a new expiry. Must
+// be called with the lock held.
+func (b *LRUCache[K, T]) setExpire(e *entry[K, T], expire time.Time) {
+	e.expire = expire
+	if x := e.ext; x != nil {
+		x.idle, x.deadline = 0, time.Time{}
+	}
+	switch {
+	case expire.IsZero() && e.index != -1:
+		HeapRemove(&b.priorityQueue, e.index)
+	case expire.IsZero():
+	case e.index == -1:
+		HeapPush(&b.priorityQueue, e)
+	default:
+		HeapFix(&b.priorityQueue, e.index)
+	}
+}
+
+// SetWithTTL adds an item expiring ttl from now, see
+// LRUCache.SetWithTTL.
+func (m *MultiLRUCache[K, T]) SetWithTTL(key K, value T, ttl time.Duration) {
+	m.cache[m.bucketNo(key)].SetWithTTL(key, value, ttl)
+}
+
+// SetSliding adds an item expiring after being idle for a while, see
+// LRUCache.SetSliding.
+func (m *MultiLRUCache[K, T]) SetSliding(key K, value T, idle, maxLifetime time.Duration) {
+	m.cache[m.bucketNo(key)].SetSliding(key, value, idle, maxLifetime)
+}
+
+// ExpiresAt gets the expiry time of a key, see LRUCache.ExpiresAt.
+func (m *MultiLRUCache[K, T]) ExpiresAt(key K) (expire time.Time, ok bool) {
+	return m.cache[m.bucketNo(key)].ExpiresAt(key)
+}
+
+// TTL gets the time left before a key expires, see LRUCache.TTL.
+func (m *MultiLRUCache[K, T]) TTL(key K) (ttl time.Duration, ok bool) {
+	return m.cache[m.bucketNo(key)].TTL(key)
+}
+
+// Touch changes the expiry of a key in place, see LRUCache.Touch.
+func (m *MultiLRUCache[K, T]) Touch(key K, expire time.Time) bool {
+	return m.cache[m.bucketNo(key)].Touch(key, expire)
+}
+
+// Persist removes the expiry of a key, see LRUCache.Persist.
+func (m *MultiLRUCache[K, T]) Persist(key K) bool {
+	return m.cache[m.bucketNo(key)].Persist(key)
+}
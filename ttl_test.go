@@ -0,0 +1,119 @@
+package lrucache
+
+import (
+	"testing"
+	"time"
+
+	"GolangLRU/lrucachetest"
+)
+
+func TestSetWithTTL(t *testing.T) {
+	t.Parallel()
+	clock := lrucachetest.NewFakeClock(time.Now())
+	b := NewLRUCache[string, string](3, WithClock(clock))
+
+	b.SetWithTTL("a", "va", time.Minute)
+	b.SetWithTTL("b", "vb", 0)
+
+	clock.Advance(59 * time.Second)
+	if _, ok := b.GetNotStale("a"); !ok {
+		t.Error("expecting hit")
+	}
+	clock.Advance(2 * time.Second)
+	if _, ok := b.GetNotStale("a"); ok {
+		t.Error("expecting miss")
+	}
+
+	clock.Advance(time.Hour)
+	if _, ok := b.GetNotStale("b"); !ok {
+		t.Error("expecting item without ttl to never expire")
+	}
+	if b.table["b"].ext != nil {
+		t.Error("expecting no extension fields allocated")
+	}
+}
+
+func TestSetSliding(t *testing.T) {
+	t.Parallel()
+	clock := lrucachetest.NewFakeClock(time.Now())
+	b := NewLRUCache[string, string](3, WithClock(clock))
+
+	b.SetSliding("a", "va", time.Minute, 0)
+	b.SetSliding("b", "vb", time.Minute, 150*time.Second)
+	b.SetWithTTL("c", "vc", 90*time.Second)
+
+	// Reads keep the items alive.
+	for i := 0; i < 2; i++ {
+		clock.Advance(50 * time.Second)
+		for _, k := range []string{"a", "b"} {
+			if _, ok := b.GetNotStale(k); !ok {
+				t.Errorf("expecting hit for %s", k)
+			}
+		}
+	}
+	if b.ExpireNow(clock.Now()) != 1 {
+		t.Error("expecting non sliding item to expire")
+	}
+
+	// b hits its maximum lifetime.
+	clock.Advance(51 * time.Second)
+	b.Get("a")
+	if _, ok := b.GetNotStale("b"); ok {
+		t.Error("expecting b to expire")
+	}
+
+	// Idle for too long.
+	clock.Advance(61 * time.Second)
+	if _, ok := b.GetNotStale("a"); ok {
+		t.Error("expecting a to expire")
+	}
+}
+
+func TestTouch(t *testing.T) {
+	t.Parallel()
+	clock := lrucachetest.NewFakeClock(time.Now())
+	b := NewLRUCache[string, string](3, WithClock(clock))
+
+	b.SetWithTTL("a", "va", time.Minute)
+	b.Set("b", "vb", time.Time{})
+	b.Set("c", "vc", time.Time{})
+
+	if ttl, ok := b.TTL("a"); !ok || ttl != time.Minute {
+		t.Errorf("expecting a minute, got %v", ttl)
+	}
+	if ttl, ok := b.TTL("b"); !ok || ttl != 0 {
+		t.Error("expecting no ttl")
+	}
+	if _, ok := b.TTL("x"); ok {
+		t.Error("expecting miss")
+	}
+
+	// a stays the least recently used.
+	if !b.Touch("a", clock.Now().Add(time.Hour)) || !b.Touch("b", clock.Now().Add(time.Second)) {
+		t.Error("expecting touch")
+	}
+	if b.Touch("x", time.Time{}) {
+		t.Error("expecting miss")
+	}
+	if expire, _ := b.ExpiresAt("a"); !expire.Equal(clock.Now().Add(time.Hour)) {
+		t.Error("expecting new expiry")
+	}
+
+	clock.Advance(2 * time.Second)
+	if n := b.Expire(); n != 1 {
+		t.Errorf("expecting b to expire, got %d", n)
+	}
+	if !b.Persist("a") {
+		t.Error("expecting persist")
+	}
+	clock.Advance(2 * time.Hour)
+	if n := b.Expire(); n != 0 {
+		t.Errorf("expecting no expiry, got %d", n)
+	}
+
+	b.Set("d", "vd", time.Time{})
+	b.Set("e", "ve", time.Time{})
+	if _, ok := b.Get("a"); ok {
+		t.Error("expecting a to be evicted")
+	}
+}
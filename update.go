@@ -0,0 +1,125 @@
+// Copyright (c) 2013 CloudFlare, Inc.
+
+package lrucache
+
+import (
+	"time"
+)
+
+// Fresh entry for a key, nil if it's missing or expired. Must be
+// called with the lock held.
+func (b *LRUCache[K, T]) fresh(key K, now time.Time) *entry[K, T] {
+	e := b.lookup(key)
+	if e == nil || e.expired(now) {
+		return nil
+	}
+	return e
+}
+
+// SetIfAbsent adds an item to the cache unless the key is already
+// there and not stale. Reports whether the item was stored.
+func (b *LRUCache[K, T]) SetIfAbsent(key K, value T, expire time.Time) bool {
+	weight := b.weigh(key, value)
+
+	b.lock.Lock()
+	defer b.unlock()
+
+	now := b.clock.Now()
+	if b.fresh(key, now) != nil {
+		return false
+	}
+	return b.set(key, value, weight, b.jitter(expire, now), now) != nil
+}
+
+// GetOrSet gets a key from the cache, making sure it's not stale, and
+// updates its LRU score. On a miss it stores value instead. Returns
+// the value in the cache and whether it was already there.
+func (b *LRUCache[K, T]) GetOrSet(key K, value T, expire time.Time) (actual T, loaded bool) {
+	weight := b.weigh(key, value)
+
+	b.lock.Lock()
+	defer b.unlock()
+
+	now := b.clock.Now()
+	if e := b.fresh(key, now); e != nil {
+		b.counters.hits.Add(1)
+		b.touchEntry(e, now)
+		return e.value, true
+	}
+	b.counters.misses.Add(1)
+	b.set(key, value, weight, b.jitter(expire, now), now)
+	return value, false
+}
+
+// CompareAndSwap replaces the value of a key, if it's not stale and
+// equal to old, with new expiring at expire. Values are compared by
+// equal, or by == when it's nil, which panics if T isn't comparable.
+// Reports whether the value was swapped.
+func (b *LRUCache[K, T]) CompareAndSwap(key K, old, new T, expire time.Time, equal func(a, b T) bool) bool {
+	weight := b.weigh(key, new)
+
+	b.lock.Lock()
+	defer b.unlock()
+
+	now := b.clock.Now()
+	e := b.fresh(key, now)
+	if e == nil {
+		return false
+	}
+	if equal == nil {
+		equal = func(x, y T) bool { return any(x) == any(y) }
+	}
+	if !equal(e.value, old) {
+		return false
+	}
+	return b.set(key, new, weight, b.jitter(expire, now), now) != nil
+}
+
+// Update calls fn with the value of a key, ok is false if it's
+// missing or stale. Unless fn returns false as the last result, the
+// value it returns is stored with the returned expiry. Returns the
+// value in the cache afterwards and whether there's one. fn runs
+// with the lock held and must not use the cache.
+func (b *LRUCache[K, T]) Update(key K, fn func(old T, ok bool) (value T, expire time.Time, store bool)) (value T, ok bool) {
+	b.lock.Lock()
+	defer b.unlock()
+
+	now := b.clock.Now()
+	e := b.fresh(key, now)
+	if e != nil {
+		value, ok = e.value, true
+	}
+
+	newValue, expire, store := fn(value, ok)
+	if !store {
+		return value, ok
+	}
+	if b.set(key, newValue, b.weigh(key, newValue), b.jitter(expire, now), now) == nil {
+		return value, ok
+	}
+	return newValue, true
+}
+
+// SetIfAbsent adds an item unless the key is there, see
+// LRUCache.SetIfAbsent.
+func (m *MultiLRUCache[K, T]) SetIfAbsent(key K, value T, expire time.Time) bool {
+	return m.cache[m.bucketNo(key)].SetIfAbsent(key, value, expire)
+}
+
+// GetOrSet gets a key from the cache or stores value, see
+// LRUCache.GetOrSet.
+func (m *MultiLRUCache[K, T]) GetOrSet(key K, value T, expire time.Time) (actual T, loaded bool) {
+	return m.cache[m.bucketNo(key)].GetOrSet(key, value, expire)
+}
+
+// CompareAndSwap replaces the value of a key if it's equal to old, see
+// LRUCache.CompareAndSwap.
+func (m *MultiLRUCache[K, T]) CompareAndSwap(key K, old, new T, expire time.Time, equal func(a, b T) bool) bool {
+	return m.cache[m.bucketNo(key)].CompareAndSwap(key, old, new, expire, equal)
+}
+
+// Update atomically modifies the value of a key, see
+// LRUCache.Update.
+func (m *MultiLRUCache[K, T]) Update(key K, fn func(old T, ok bool) (value T, expire time.Time, store bool)) (value T, ok bool) {
+	return m.cache[m.bucketNo(key)].Update(key, fn)
+}
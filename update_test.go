@@ -0,0 +1,106 @@
+package lrucache
+
+import (
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"GolangLRU/lrucachetest"
+)
+
+func TestSetIfAbsent(t *testing.T) {
+	t.Parallel()
+	clock := lrucachetest.NewFakeClock(time.Date(2013, 1, 1, 0, 0, 0, 0, time.UTC))
+	b := NewLRUCache[string, string](10, WithClock(clock))
+
+	if !b.SetIfAbsent("a", "va", clock.Now().Add(time.Minute)) {
+		t.Error("expecting set")
+	}
+	if b.SetIfAbsent("a", "vb", time.Time{}) {
+		t.Error("expecting no set")
+	}
+	if v, _ := b.Get("a"); v != "va" {
+		t.Error("expecting value to be kept")
+	}
+
+	clock.Advance(2 * time.Minute)
+	if !b.SetIfAbsent("a", "vc", time.Time{}) {
+		t.Error("expecting stale entry to be replaced")
+	}
+
+	if v, loaded := b.GetOrSet("a", "vd", time.Time{}); !loaded || v != "vc" {
+		t.Error("expecting existing value")
+	}
+	if v, loaded := b.GetOrSet("b", "vb", time.Time{}); loaded || v != "vb" {
+		t.Error("expecting value to be stored")
+	}
+	if v, _ := b.Get("b"); v != "vb" {
+		t.Error("expecting hit")
+	}
+}
+
+func TestCompareAndSwap(t *testing.T) {
+	t.Parallel()
+	b := NewLRUCache[string, []int](10)
+
+	b.Set("a", []int{1}, time.Time{})
+	if b.CompareAndSwap("a", []int{2}, []int{3}, time.Time{}, slices.Equal[[]int]) {
+		t.Error("expecting no swap")
+	}
+	if !b.CompareAndSwap("a", []int{1}, []int{3}, time.Time{}, slices.Equal[[]int]) {
+		t.Error("expecting swap")
+	}
+	if v, _ := b.Get("a"); !slices.Equal(v, []int{3}) {
+		t.Error("expecting swapped value")
+	}
+	if b.CompareAndSwap("b", nil, []int{3}, time.Time{}, slices.Equal[[]int]) {
+		t.Error("expecting no swap of missing key")
+	}
+
+	c := NewLRUCache[string, int](10)
+	c.Set("a", 1, time.Time{})
+	if !c.CompareAndSwap("a", 1, 2, time.Time{}, nil) {
+		t.Error("expecting swap")
+	}
+}
+
+func TestUpdate(t *testing.T) {
+	t.Parallel()
+	m := NewMultiLRUCache[string, int](4, 10)
+
+	incr := func(old int, ok bool) (int, time.Time, bool) {
+		return old + 1, time.Time{}, true
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				m.Update("n", incr)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if v, ok := m.Get("n"); !ok || v != 800 {
+		t.Errorf("expecting 800, got %d", v)
+	}
+
+	v, ok := m.Update("n", func(old int, ok bool) (int, time.Time, bool) {
+		return 0, time.Time{}, false
+	})
+	if !ok || v != 800 {
+		t.Error("expecting value to be kept")
+	}
+	if _, ok := m.Update("missing", func(old int, ok bool) (int, time.Time, bool) {
+		if ok {
+			t.Error("expecting missing key")
+		}
+		return 0, time.Time{}, false
+	}); ok {
+		t.Error("expecting no value")
+	}
+}